@@ -0,0 +1,270 @@
+package blackjack
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Mistake is a decision that differs from basic strategy for the table's rules.
+// Cost is the money the decision is expected to lose compared to the best move. It is
+// Approximate when splitting was the best move or the one played, see Expectation.
+type Mistake struct {
+	Round       int         `json:"round"`
+	Seat        int         `json:"seat"`
+	Name        string      `json:"name"`
+	Hand        []deck.Card `json:"hand"`
+	Dealer      deck.Card   `json:"dealer"`
+	Situation   string      `json:"situation"`
+	Played      string      `json:"played"`
+	Best        string      `json:"best"`
+	Cost        float64     `json:"cost"`
+	Approximate bool        `json:"approximate,omitempty"`
+}
+
+func (m Mistake) String() string {
+	cost := fmt.Sprintf("%.2f", m.Cost)
+	if m.Approximate {
+		cost = "about " + cost
+	}
+	return fmt.Sprintf("round %d, %s: %s played %s instead of %s, costing %s", m.Round, m.Name, m.Situation, m.Played, m.Best, cost)
+}
+
+// DecisionSummary totals the decisions made in a situation, e.g "soft 18 vs 9",
+// or by a player.
+type DecisionSummary struct {
+	Name      string  `json:"name"`
+	Decisions int     `json:"decisions"`
+	Mistakes  int     `json:"mistakes"`
+	Cost      float64 `json:"cost"`
+}
+
+// Analysis compares every decision of a hand history with basic strategy.
+// Situations are sorted by cost, the costliest first, and Players by name.
+type Analysis struct {
+	Rounds     int               `json:"rounds"`
+	Decisions  int               `json:"decisions"`
+	Cost       float64           `json:"cost"`
+	Mistakes   []Mistake         `json:"mistakes"`
+	Situations []DecisionSummary `json:"situations"`
+	Players    []DecisionSummary `json:"players"`
+}
+
+func (a Analysis) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rounds:     %d\n", a.Rounds)
+	fmt.Fprintf(&b, "Decisions:  %d\n", a.Decisions)
+	fmt.Fprintf(&b, "Mistakes:   %d\n", len(a.Mistakes))
+	fmt.Fprintf(&b, "Cost:       %.2f\n", a.Cost)
+	fmt.Fprintf(&b, "By player:\n")
+	for _, p := range a.Players {
+		fmt.Fprintf(&b, "  %-20s %d of %d decisions wrong, costing %.2f\n", p.Name, p.Mistakes, p.Decisions, p.Cost)
+	}
+	fmt.Fprintf(&b, "By situation:\n")
+	for _, s := range a.Situations {
+		if s.Mistakes > 0 {
+			fmt.Fprintf(&b, "  %-20s %d of %d decisions wrong, costing %.2f\n", s.Name, s.Mistakes, s.Decisions, s.Cost)
+		}
+	}
+	fmt.Fprintf(&b, "Mistakes:\n")
+	for _, m := range a.Mistakes {
+		fmt.Fprintf(&b, "  %s\n", m)
+	}
+	return b.String()
+}
+
+// analyzer holds the basic strategy and the calculator of a round.
+type analyzer struct {
+	chart *Chart
+	calc  *Calculator
+	err   error
+}
+
+// Analyze replays the rounds of a hand history, see ReadHistory, and compares every
+// insurance decision and action with basic strategy for the rules of the round.
+// Mistakes are costed with a Calculator for the cards the player has not seen, the
+// ones left in the shoe and the dealer's hole card, which only approximates splits.
+func Analyze(rounds []RoundHistory) (Analysis, error) {
+	a := Analysis{Rounds: len(rounds)}
+	analyzers := map[Rules]*analyzer{}
+	situations := map[string]*DecisionSummary{}
+	players := map[string]*DecisionSummary{}
+
+	record := func(m Mistake, wrong bool) {
+		a.Decisions++
+		for _, s := range []*DecisionSummary{total(situations, m.Situation), total(players, m.Name)} {
+			s.Decisions++
+			if wrong {
+				s.Mistakes++
+				s.Cost += m.Cost
+			}
+		}
+		if wrong {
+			a.Cost += m.Cost
+			a.Mistakes = append(a.Mistakes, m)
+		}
+	}
+
+	for _, h := range rounds {
+		an, ok := analyzers[h.Rules]
+		if !ok {
+			an = &analyzer{chart: BasicStrategyChart(h.Rules)}
+			analyzers[h.Rules] = an
+		}
+		// The values cached for the shoe of one round are of no use to the next.
+		calc, err := NewCalculator(h.Rules)
+		if err != nil {
+			return Analysis{}, err
+		}
+		an.calc = calc
+		err = replay(h, func(e *Engine, ev Event) {
+			if an.err != nil {
+				return
+			}
+			switch ev := ev.(type) {
+			case InsuranceDecided:
+				record(an.insurance(e, h.Round, ev))
+			case PlayerActed:
+				m, wrong, err := an.action(e, h.Round, ev)
+				if err != nil {
+					an.err = fmt.Errorf("blackjack: round %d: %w", h.Round, err)
+					return
+				}
+				record(m, wrong)
+			}
+		})
+		if err == nil {
+			err = an.err
+		}
+		if err != nil {
+			return Analysis{}, err
+		}
+	}
+
+	for _, s := range situations {
+		a.Situations = append(a.Situations, *s)
+	}
+	slices.SortFunc(a.Situations, func(x, y DecisionSummary) int {
+		return cmp.Or(cmp.Compare(y.Cost, x.Cost), cmp.Compare(y.Mistakes, x.Mistakes), cmp.Compare(x.Name, y.Name))
+	})
+	for _, p := range players {
+		a.Players = append(a.Players, *p)
+	}
+	slices.SortFunc(a.Players, func(x, y DecisionSummary) int {
+		return cmp.Compare(x.Name, y.Name)
+	})
+	return a, nil
+}
+
+func total(totals map[string]*DecisionSummary, name string) *DecisionSummary {
+	s, ok := totals[name]
+	if !ok {
+		s = &DecisionSummary{Name: name}
+		totals[name] = s
+	}
+	return s
+}
+
+// insurance judges an insurance decision. As basic strategy, the best move is the one
+// for a full shoe, so insurance is never taken and even money is judged against the
+// blackjack payout, making it the best move at a 6:5 table.
+func (an *analyzer) insurance(e *Engine, round int, ev InsuranceDecided) (Mistake, bool) {
+	s := e.seats[ev.Seat]
+	h := s.hands[0]
+	m := an.mistake(e, round, ev.Seat, h.hand)
+	m.Situation = "insurance"
+	if h.isNatural() {
+		m.Situation = "even money"
+	}
+
+	taken, declined := insuranceValues(e.rules, h.isNatural(), NewComposition(e.rules.Decks))
+	m.Played, m.Best = "decline", "decline"
+	if ev.Taken {
+		m.Played = "take"
+	}
+	if taken > declined {
+		m.Best = "take"
+	}
+	if m.Played == m.Best {
+		return m, false
+	}
+	taken, declined = insuranceValues(e.rules, h.isNatural(), unseen(e))
+	loss := taken - declined
+	if ev.Taken {
+		loss = -loss
+	}
+	m.Cost = max(loss, 0) * float64(s.bet)
+	return m, true
+}
+
+// insuranceValues returns the values of taking and declining insurance, or even money
+// for a blackjack, per unit of the bet when the hole card is drawn from the shoe.
+func insuranceValues(rules Rules, natural bool, shoe Composition) (taken, declined float64) {
+	ten := float64(shoe[10]) / float64(shoe.Cards())
+	if natural {
+		// Without even money the blackjack pushes against a dealer blackjack.
+		return 1, rules.BlackjackPayout.Of(1) * (1 - ten)
+	}
+	// Insurance pays 2 to 1 on half the bet when the hole card is a ten.
+	return (2*ten - (1 - ten)) / 2, 0
+}
+
+// action judges an action on the hand whose turn it is.
+func (an *analyzer) action(e *Engine, round int, ev PlayerActed) (Mistake, bool, error) {
+	h := e.seats[ev.Seat].hands[ev.Hand]
+	legal := e.LegalActions()
+	up := e.UpCard()
+	m := an.mistake(e, round, ev.Seat, h.hand)
+	m.Situation = situation(h.hand, up, slices.Contains(legal, Split))
+
+	best := an.chart.Action(h.hand, up, legal, e.rules.DoubleAfterSplit)
+	m.Played, m.Best = ev.Action.String(), best.String()
+	if ev.Action == best {
+		return m, false, nil
+	}
+	x, err := an.calc.Evaluate(h.hand, up, unseen(e))
+	if err != nil {
+		return m, false, err
+	}
+	m.Cost = max(x.Of(best)-x.Of(ev.Action), 0) * float64(h.bet)
+	m.Approximate = best == Split || ev.Action == Split
+	return m, true, nil
+}
+
+// unseen returns the cards the players have not seen during their turns, the ones
+// left in the shoe and the dealer's hole card.
+func unseen(e *Engine) Composition {
+	shoe := CompositionOf(e.shoe.Cards()[e.shoe.Dealt():])
+	if len(e.dealer.Hand) > 1 && !e.revealed {
+		shoe[cardValue(e.dealer.Hand[1])]++
+	}
+	return shoe
+}
+
+func (an *analyzer) mistake(e *Engine, round, seat int, hand Hand) Mistake {
+	return Mistake{
+		Round:  round,
+		Seat:   seat,
+		Name:   e.seats[seat].name,
+		Hand:   slices.Clone(hand.Hand),
+		Dealer: e.UpCard(),
+	}
+}
+
+// situation names a hand against the dealer's up card, e.g "hard 16 vs 10",
+// "soft 18 vs 9" or "pair of 8s vs A".
+func situation(hand Hand, up deck.Card, canSplit bool) string {
+	dealer := upCards[upCardIndex(up)]
+	switch {
+	case canSplit && hand.Hand[0].Type == deck.ACE:
+		return "pair of aces vs " + dealer
+	case canSplit:
+		return fmt.Sprintf("pair of %ds vs %s", cardValue(hand.Hand[0]), dealer)
+	case hand.IsSoft():
+		return fmt.Sprintf("soft %d vs %s", hand.Value(), dealer)
+	}
+	return fmt.Sprintf("hard %d vs %s", hand.Value(), dealer)
+}
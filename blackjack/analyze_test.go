@@ -0,0 +1,70 @@
+package blackjack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func TestAnalyze(t *testing.T) {
+	history := recordRounds(t, 200)
+	a, err := Analyze(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	players := map[string]DecisionSummary{}
+	for _, p := range a.Players {
+		players[p.Name] = p
+	}
+	if basic := players["Basic"]; basic.Decisions == 0 || basic.Mistakes != 0 {
+		t.Fatalf("expected basic strategy to make no mistakes, got %+v", basic)
+	}
+	mimic := players["Mimic"]
+	if mimic.Mistakes == 0 || mimic.Cost <= 0 {
+		t.Fatalf("expected mimicking the dealer to make costly mistakes, got %+v", mimic)
+	}
+	if len(a.Mistakes) != mimic.Mistakes || math.Abs(a.Cost-mimic.Cost) > 1e-9 {
+		t.Fatalf("expected the totals to add up, got\n%s", a)
+	}
+
+	var insurance bool
+	for _, m := range a.Mistakes {
+		if m.Name != "Mimic" || m.Played == m.Best || m.Cost < 0 {
+			t.Fatalf("unexpected mistake %+v", m)
+		}
+		if split := m.Played == "split" || m.Best == "split"; m.Approximate != split {
+			t.Fatalf("expected only costs of splits to be approximate, got %+v", m)
+		}
+		insurance = insurance || m.Situation == "insurance" || m.Situation == "even money"
+	}
+	if !insurance {
+		t.Fatalf("expected insurance to be reported as a mistake, got\n%s", a)
+	}
+	for i := 1; i < len(a.Situations); i++ {
+		if a.Situations[i].Cost > a.Situations[i-1].Cost {
+			t.Fatalf("expected situations sorted by cost, got\n%s", a)
+		}
+	}
+}
+
+func TestSituation(t *testing.T) {
+	tests := []struct {
+		hand     Hand
+		dealer   deck.Type
+		canSplit bool
+		want     string
+	}{
+		{hand(deck.TEN, deck.SIX), deck.TEN, false, "hard 16 vs 10"},
+		{hand(deck.ACE, deck.SEVEN), deck.NINE, false, "soft 18 vs 9"},
+		{hand(deck.EIGHT, deck.EIGHT), deck.ACE, true, "pair of 8s vs A"},
+		{hand(deck.EIGHT, deck.EIGHT), deck.ACE, false, "hard 16 vs A"},
+		{hand(deck.ACE, deck.ACE), deck.SIX, true, "pair of aces vs 6"},
+	}
+	for _, test := range tests {
+		if got := situation(test.hand, deck.Card{Type: test.dealer}, test.canSplit); got != test.want {
+			t.Errorf("expected %q, got %q", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Bankroll is the money a player keeps between sessions and the sessions played with it.
+type Bankroll struct {
+	Balance  float64   `json:"balance"`
+	Sessions []Session `json:"sessions"`
+}
+
+// Session is one visit to the table.
+type Session struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Rounds int       `json:"rounds"`
+	Net    float64   `json:"net"`
+}
+
+// BankrollStore keeps the bankroll of every player in a JSON file.
+type BankrollStore struct {
+	Filepath string
+}
+
+// Get returns the bankroll of a player, ok is false for a player the store does not know.
+func (s BankrollStore) Get(name string) (b Bankroll, ok bool, err error) {
+	bankrolls, err := s.getBankrolls()
+	if err != nil {
+		return Bankroll{}, false, err
+	}
+	b, ok = bankrolls[name]
+	return b, ok, nil
+}
+
+// Set replaces the balance of a player, e.g to buy in again after going broke.
+func (s BankrollStore) Set(name string, balance float64) error {
+	bankrolls, err := s.getBankrolls()
+	if err != nil {
+		return err
+	}
+
+	b := bankrolls[name]
+	b.Balance = balance
+	bankrolls[name] = b
+
+	return s.saveBankrolls(bankrolls)
+}
+
+// Record adds a session to a player's bankroll and updates the balance by its net result.
+func (s BankrollStore) Record(name string, session Session) error {
+	bankrolls, err := s.getBankrolls()
+	if err != nil {
+		return err
+	}
+
+	b, ok := bankrolls[name]
+	if !ok {
+		return fmt.Errorf("blackjack: no bankroll for %q", name)
+	}
+	b.Balance += session.Net
+	b.Sessions = append(b.Sessions, session)
+	bankrolls[name] = b
+
+	return s.saveBankrolls(bankrolls)
+}
+
+// Delete removes the bankroll of a player.
+func (s BankrollStore) Delete(name string) error {
+	bankrolls, err := s.getBankrolls()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := bankrolls[name]; !ok {
+		return fmt.Errorf("blackjack: no bankroll for %q", name)
+	}
+	delete(bankrolls, name)
+
+	return s.saveBankrolls(bankrolls)
+}
+
+func (s BankrollStore) getBankrolls() (map[string]Bankroll, error) {
+	bankrolls := map[string]Bankroll{}
+	// The file is only created when a bankroll is saved.
+	data, err := os.ReadFile(s.Filepath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return bankrolls, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return bankrolls, nil
+	}
+	if err := json.Unmarshal(data, &bankrolls); err != nil {
+		return nil, fmt.Errorf("blackjack: corrupted bankroll file: %w", err)
+	}
+	return bankrolls, nil
+}
+
+func (s BankrollStore) saveBankrolls(bankrolls map[string]Bankroll) error {
+	data, err := json.MarshalIndent(bankrolls, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Writing to a temporary file renamed into place keeps the bankrolls intact
+	// when the write fails halfway.
+	tmp, err := os.CreateTemp(filepath.Dir(s.Filepath), filepath.Base(s.Filepath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Filepath)
+}
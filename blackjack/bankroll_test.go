@@ -0,0 +1,57 @@
+package blackjack
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBankrollStore(t *testing.T) {
+	store := BankrollStore{Filepath: filepath.Join(t.TempDir(), "bankrolls.json")}
+
+	if _, ok, err := store.Get("Alice"); err != nil || ok {
+		t.Fatalf("expected no bankroll in a new store, got %v (%v)", ok, err)
+	}
+	if _, err := os.Stat(store.Filepath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected Get not to create the file, got %v", err)
+	}
+	if err := store.Record("Alice", Session{Net: 10}); err == nil {
+		t.Fatal("expected an error recording a session without a bankroll")
+	}
+
+	if err := store.Set("Alice", 100); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{Start: start, End: start.Add(time.Hour), Rounds: 60, Net: -40},
+		{Start: start.Add(24 * time.Hour), End: start.Add(25 * time.Hour), Rounds: 55, Net: 15.5},
+	}
+	for _, s := range sessions {
+		if err := store.Record("Alice", s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, ok, err := store.Get("Alice")
+	if err != nil || !ok {
+		t.Fatalf("expected Alice's bankroll, got %v (%v)", ok, err)
+	}
+	if b.Balance != 75.5 || len(b.Sessions) != 2 || !b.Sessions[1].Start.Equal(sessions[1].Start) {
+		t.Fatalf("expected a balance of 75.5 after two sessions, got %+v", b)
+	}
+	// Saving renames a temporary file into place.
+	if files, _ := os.ReadDir(filepath.Dir(store.Filepath)); len(files) != 1 {
+		t.Fatalf("expected only the bankroll file, got %v", files)
+	}
+
+	if err := store.Delete("Alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("Alice"); err == nil {
+		t.Fatal("expected an error deleting a missing bankroll")
+	}
+}
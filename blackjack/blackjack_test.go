@@ -159,3 +159,225 @@ func TestQuadAces(t *testing.T) {
 		}
 	})
 }
+
+// scriptedAI answers every decision with a fixed choice.
+type scriptedAI struct {
+	insurance, surrender, split, double bool
+	hitBelow                            int
+	bet                                 int
+}
+
+func (ai scriptedAI) DecideInsurance(Hand) bool            { return ai.insurance }
+func (ai scriptedAI) DecideSurrender(Hand, deck.Card) bool { return ai.surrender }
+func (ai scriptedAI) DecideSplit(Hand, deck.Card) bool     { return ai.split }
+func (ai scriptedAI) DoubleDown(Hand, deck.Card) bool      { return ai.double }
+func (ai scriptedAI) DecideBet() int                       { return ai.bet }
+func (ai scriptedAI) DecideHit(h Hand, _ deck.Card) bool {
+	return h.Value() < ai.hitBelow
+}
+
+// playRound plays a single round for one AI player, dealing cards in the given
+// order: dealer up card, player, dealer hole card, player, then every draw.
+func playRound(t *testing.T, ai scriptedAI, cards string) *Engine {
+	t.Helper()
+	return playRoundWithRules(t, DefaultRules(), ai, cards)
+}
+
+func playRoundWithRules(t *testing.T, rules Rules, ai scriptedAI, cards string) *Engine {
+	t.Helper()
+	stacked, err := deck.ParseDeck(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe, err := deck.NewShoeFromCards(stacked, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := NewEngine(rules, WithShoe(shoe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddPlayer("AI", ai); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+	if e.Phase() != PhaseIdle {
+		t.Fatalf("expected round to be settled, got phase %s", e.Phase())
+	}
+	return e
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("resplit", func(t *testing.T) {
+		// Player splits eights three times and hits every hand below 12.
+		g := playRound(t, scriptedAI{split: true, hitBelow: 12, bet: 10},
+			"Td 8s 7c 8h 8d 3c Kc 8c 2h 9s 5s Kh")
+		p := g.seats[0]
+
+		if len(p.hands) != 4 {
+			t.Fatalf("expected 4 hands, got %d", len(p.hands))
+		}
+		// Dealer stands on 17: 8+3+K, 8+2+9 and 8+K win, 8+5 loses.
+		if p.winnings != 20 {
+			t.Fatalf("expected winnings of 20, got %v", p.winnings)
+		}
+	})
+
+	t.Run("split aces get one card", func(t *testing.T) {
+		g := playRound(t, scriptedAI{split: true, hitBelow: 21, bet: 10},
+			"9d As 7c Ah 2c 3d Ks")
+		p := g.seats[0]
+
+		for i, h := range p.hands {
+			if len(h.hand.Hand) != 2 {
+				t.Fatalf("expected hand %d to have 2 cards, got %v", i, h.hand.Hand)
+			}
+		}
+		// Dealer draws K to 26 and busts.
+		if p.winnings != 20 {
+			t.Fatalf("expected winnings of 20, got %v", p.winnings)
+		}
+	})
+}
+
+func TestSurrender(t *testing.T) {
+	g := playRound(t, scriptedAI{surrender: true, bet: 10}, "Td 6s 7c Kh")
+
+	if g.seats[0].winnings != -5 {
+		t.Fatalf("expected to lose half the bet, got %v", g.seats[0].winnings)
+	}
+}
+
+func TestInsurance(t *testing.T) {
+	t.Run("dealer blackjack", func(t *testing.T) {
+		g := playRound(t, scriptedAI{insurance: true, bet: 10}, "As 9s Kc Kh")
+
+		// Lose the bet of 10, insurance of 5 pays 2:1.
+		if g.seats[0].winnings != 0 {
+			t.Fatalf("expected to break even, got %v", g.seats[0].winnings)
+		}
+	})
+
+	t.Run("no dealer blackjack", func(t *testing.T) {
+		g := playRound(t, scriptedAI{insurance: true, bet: 10}, "As 9s 6c Kh Tc")
+
+		// Dealer draws to 17 against 19, insurance is lost.
+		if g.seats[0].winnings != 5 {
+			t.Fatalf("expected winnings of 5, got %v", g.seats[0].winnings)
+		}
+	})
+
+	t.Run("even money", func(t *testing.T) {
+		g := playRound(t, scriptedAI{insurance: true, bet: 10}, "As As Kc Kh")
+
+		if g.seats[0].winnings != 10 {
+			t.Fatalf("expected even money, got %v", g.seats[0].winnings)
+		}
+	})
+
+	t.Run("even money without dealer blackjack", func(t *testing.T) {
+		g := playRound(t, scriptedAI{insurance: true, bet: 10}, "As As 6c Kh")
+
+		if g.seats[0].winnings != 10 {
+			t.Fatalf("expected even money, got %v", g.seats[0].winnings)
+		}
+	})
+}
+
+func TestIsSoft(t *testing.T) {
+	tests := map[string]bool{
+		"As 6h":    true,
+		"As 6h Kd": false,
+		"As Ah 5c": true,
+		"9s 8h":    false,
+	}
+
+	for cards, want := range tests {
+		hand, err := deck.ParseDeck(cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := Hand{Hand: hand}
+		if h.IsSoft() != want {
+			t.Fatalf("%s: expected soft %v", cards, want)
+		}
+	}
+}
+
+func TestDealerSoft17(t *testing.T) {
+	soft17 := Hand{Hand: []deck.Card{{Suit: deck.HEART, Type: deck.ACE}, {Suit: deck.HEART, Type: deck.SIX}}}
+	hard17 := Hand{Hand: []deck.Card{{Suit: deck.HEART, Type: deck.ACE}, {Suit: deck.HEART, Type: deck.SIX}, {Suit: deck.CLUB, Type: deck.KING}}}
+
+	h17 := Rules{DealerHitsSoft17: true}.DealerStrategy()
+	s17 := Rules{DealerHitsSoft17: false}.DealerStrategy()
+
+	if !h17.DecideHit(soft17) || h17.DecideHit(hard17) {
+		t.Fatal("expected H17 dealer to hit soft 17 only")
+	}
+	if s17.DecideHit(soft17) || s17.DecideHit(hard17) {
+		t.Fatal("expected S17 dealer to stand on 17")
+	}
+}
+
+func TestBlackjackPayout(t *testing.T) {
+	tests := map[Payout]float64{
+		{3, 2}: 15,
+		{6, 5}: 12,
+		{1, 1}: 10,
+	}
+
+	for payout, want := range tests {
+		rules := DefaultRules()
+		rules.BlackjackPayout = payout
+		g := playRoundWithRules(t, rules, scriptedAI{bet: 10}, "9s As 7c Kh")
+
+		if g.seats[0].winnings != want {
+			t.Fatalf("%s: expected winnings of %v, got %v", payout, want, g.seats[0].winnings)
+		}
+	}
+}
+
+func TestDoubleRules(t *testing.T) {
+	rules := DefaultRules()
+	rules.DoubleOn = DoubleTenOrEleven
+
+	// The AI wants to double 9 but is only allowed to hit.
+	g := playRoundWithRules(t, rules, scriptedAI{double: true, hitBelow: 17, bet: 10}, "Ts 5s 7c 4h 3c Kd")
+	if h := g.seats[0].hands[0]; h.doubled || h.bet != 10 {
+		t.Fatalf("expected hand not to be doubled, got bet %d", h.bet)
+	}
+
+	// Doubling 10 draws exactly one card.
+	g = playRoundWithRules(t, rules, scriptedAI{double: true, hitBelow: 21, bet: 10}, "Ts 6s 7c 4h 3c Kd")
+	if h := g.seats[0].hands[0]; !h.doubled || h.bet != 20 || len(h.hand.Hand) != 3 {
+		t.Fatalf("expected hand to be doubled with one card, got %v", h.hand.Hand)
+	}
+	if g.seats[0].winnings != -20 {
+		t.Fatalf("expected to lose 20 with 13 against 17, got %v", g.seats[0].winnings)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	rules := DefaultRules()
+	rules.Decks = 0
+
+	if _, err := SetupSimulation(rules, scriptedAI{}, 1); err == nil {
+		t.Fatal("expected error for zero decks")
+	}
+}
+
+func TestTableLimits(t *testing.T) {
+	rules := DefaultRules()
+	rules.MinBet, rules.MaxBet = 25, 10
+	if err := rules.Validate(); err == nil {
+		t.Fatal("expected error for a maximum bet below the minimum")
+	}
+
+	rules.MaxBet = 1000
+	if got := rules.String(); got != "6 decks, H17, blackjack pays 3:2, bets 25 to 1000" {
+		t.Fatalf("unexpected rules description %q", got)
+	}
+}
@@ -0,0 +1,411 @@
+// Package bot runs blackjack AIs as external processes, so that bots written in any
+// language can play in simulations and tournaments.
+//
+// A bot reads requests on stdin and writes replies on stdout, one JSON object per line.
+// Requests of type "bet", "insurance", "action" and "sideBet" expect exactly one reply
+// line, the others are notices a bot can ignore:
+//
+//	{"type":"seat","seat":0,"rules":{...}}                   seated at a table with the rules
+//	{"type":"bet","state":{...}}                             reply {"bet":10}, 0 sits the round out
+//	{"type":"insurance","hand":["As","Kh"],"state":{...}}    reply {"take":false}
+//	{"type":"action","hand":["Ts","6h"],"dealer":"9c","legal":["hit","stand"],"state":{...}}
+//	                                                         reply {"action":"hit"}
+//	{"type":"sideBet","sideBet":{"name":"21+3",...},"state":{...}}  reply {"bet":0}
+//	{"type":"card","card":"7d"}                              a card was revealed at the table
+//	{"type":"shuffle","decks":6}                             the shoe was reshuffled
+//
+// Cards are written as their rank and a lower case suit, see deck.Card.MarshalText,
+// e.g "As" for the ace of spades or "Th" for the ten of hearts.
+//
+// The state is the table as the players see it, see blackjack.State. A bot that does
+// not reply in time, replies with an illegal action or exits fails: the process is
+// stopped and a fallback AI, or sitting out, takes over for the rest of the game.
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Request types sent to a bot.
+const (
+	// RequestBet asks for the bet of the round.
+	RequestBet = "bet"
+	// RequestInsurance asks whether to take insurance, or even money for a blackjack.
+	RequestInsurance = "insurance"
+	// RequestAction asks for one of the Legal actions on the Hand.
+	RequestAction = "action"
+	// RequestSideBet asks for the amount wagered on a side bet offered at the table.
+	RequestSideBet = "sideBet"
+	// NoticeSeat tells the bot its seat and the table's rules.
+	NoticeSeat = "seat"
+	// NoticeCard tells the bot about a card revealed at the table.
+	NoticeCard = "card"
+	// NoticeShuffle tells the bot the shoe was reshuffled.
+	NoticeShuffle = "shuffle"
+)
+
+// Request is a line sent to a bot.
+type Request struct {
+	Type    string             `json:"type"`
+	Seat    *int               `json:"seat,omitempty"`
+	Rules   *blackjack.Rules   `json:"rules,omitempty"`
+	State   *blackjack.State   `json:"state,omitempty"`
+	Hand    []deck.Card        `json:"hand,omitempty"`
+	Dealer  *deck.Card         `json:"dealer,omitempty"`
+	Legal   []blackjack.Action `json:"legal,omitempty"`
+	SideBet *blackjack.SideBet `json:"sideBet,omitempty"`
+	Card    *deck.Card         `json:"card,omitempty"`
+	Decks   int                `json:"decks,omitempty"`
+}
+
+// Reply is a line sent back by a bot.
+type Reply struct {
+	Bet    int               `json:"bet,omitempty"`
+	Take   bool              `json:"take,omitempty"`
+	Action *blackjack.Action `json:"action,omitempty"`
+}
+
+var (
+	ErrTimeout = errors.New("bot: no reply in time")
+	ErrExited  = errors.New("bot: exited")
+	ErrIllegal = errors.New("bot: illegal action")
+)
+
+// Type Options is used to configure Start.
+//
+// MoveTimeout: time a bot has to reply to a request, the first one including the
+// time the process takes to start. Defaults to 1 second. Set with WithMoveTimeout function
+//
+// Fallback: AI making the decisions once the bot failed. When not set the player
+// sits out, declines insurance and stands. Set with WithFallback function
+type Options struct {
+	MoveTimeout time.Duration
+	Fallback    blackjack.AI
+}
+
+// type OptionFunc acts a wrapper for functional
+// options used for configuration in Start
+type OptionFunc func(*Options)
+
+// Option that sets the time a bot has to reply to a request.
+func WithMoveTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.MoveTimeout = d
+	}
+}
+
+// Option that sets the AI taking over when the bot fails.
+func WithFallback(ai blackjack.AI) OptionFunc {
+	return func(o *Options) {
+		o.Fallback = ai
+	}
+}
+
+// Bot is a blackjack.AI played by an external process. It also implements
+// blackjack.ActionChooser, CardObserver, SideBetter and SeatObserver, so the bot is
+// told everything a Go AI could know. Like the engine, a Bot is not safe for
+// concurrent use.
+type Bot struct {
+	options Options
+	cmd     *exec.Cmd
+	stdin   *os.File
+	stdout  *os.File
+	enc     *json.Encoder
+	lines   chan []byte
+	done    chan struct{}
+	once    sync.Once
+	err     error
+	engine  *blackjack.Engine
+}
+
+// Start runs the command as a bot. Its stdin and stdout are used for the protocol,
+// stderr is left as set on the command, e.g to os.Stderr to see the bot's logs.
+func Start(cmd *exec.Cmd, options ...OptionFunc) (*Bot, error) {
+	b := newBot(options)
+	stdin, botStdin, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	botStdout, stdout, err := os.Pipe()
+	if err != nil {
+		stdin.Close()
+		botStdin.Close()
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout = stdin, stdout
+	err = cmd.Start()
+	// The process has its own copies of its ends of the pipes.
+	stdin.Close()
+	stdout.Close()
+	if err != nil {
+		botStdin.Close()
+		botStdout.Close()
+		return nil, err
+	}
+
+	b.cmd, b.stdin, b.stdout = cmd, botStdin, botStdout
+	b.enc = json.NewEncoder(botStdin)
+	go b.read()
+	return b, nil
+}
+
+func newBot(options []OptionFunc) *Bot {
+	o := Options{MoveTimeout: time.Second}
+	for _, option := range options {
+		option(&o)
+	}
+	return &Bot{options: o, lines: make(chan []byte), done: make(chan struct{})}
+}
+
+// read hands the lines written by the bot over to ask until the bot exits.
+func (b *Bot) read() {
+	defer close(b.lines)
+	s := bufio.NewScanner(b.stdout)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		select {
+		case b.lines <- slices.Clone(s.Bytes()):
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Err returns why the bot failed, nil while it plays.
+func (b *Bot) Err() error {
+	return b.err
+}
+
+// Close stops the bot, closing its stdin and killing it when it does not exit in
+// time, and returns why it failed if it did.
+func (b *Bot) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+		if b.cmd == nil {
+			return
+		}
+		b.stdin.Close()
+		exited := make(chan error, 1)
+		go func() { exited <- b.cmd.Wait() }()
+		select {
+		case err := <-exited:
+			if err != nil && b.err == nil {
+				b.err = fmt.Errorf("%w: %w", ErrExited, err)
+			}
+		case <-time.After(b.options.MoveTimeout):
+			b.cmd.Process.Kill()
+			<-exited
+		}
+		b.stdout.Close()
+	})
+	return b.err
+}
+
+// fail stops the bot after its first error.
+func (b *Bot) fail(err error) {
+	if b.err != nil {
+		return
+	}
+	b.err = err
+	if b.cmd != nil {
+		b.cmd.Process.Kill()
+	}
+}
+
+func (b *Bot) send(r Request) error {
+	if b.err != nil {
+		return b.err
+	}
+	// A bot that stopped reading must not block the game.
+	b.stdin.SetWriteDeadline(time.Now().Add(b.options.MoveTimeout))
+	if err := b.enc.Encode(r); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			err = ErrTimeout
+		}
+		b.fail(fmt.Errorf("bot: sending %s: %w", r.Type, err))
+	}
+	return b.err
+}
+
+// ask sends a request with the table's state and waits for the reply.
+func (b *Bot) ask(r Request) (Reply, error) {
+	if b.engine != nil {
+		st := b.engine.State()
+		r.State = &st
+	}
+	if err := b.send(r); err != nil {
+		return Reply{}, err
+	}
+
+	timer := time.NewTimer(b.options.MoveTimeout)
+	defer timer.Stop()
+	select {
+	case line, ok := <-b.lines:
+		if !ok {
+			b.fail(fmt.Errorf("%w waiting for %s", ErrExited, r.Type))
+			return Reply{}, b.err
+		}
+		var reply Reply
+		if err := json.Unmarshal(line, &reply); err != nil {
+			b.fail(fmt.Errorf("bot: reply to %s: %w", r.Type, err))
+			return Reply{}, b.err
+		}
+		return reply, nil
+	case <-timer.C:
+		b.fail(fmt.Errorf("%w to %s after %v", ErrTimeout, r.Type, b.options.MoveTimeout))
+		return Reply{}, b.err
+	}
+}
+
+func (b *Bot) ObserveSeat(e *blackjack.Engine, seat int) {
+	b.engine = e
+	rules := e.Rules()
+	b.send(Request{Type: NoticeSeat, Seat: &seat, Rules: &rules})
+}
+
+func (b *Bot) ObserveCard(c deck.Card) {
+	b.send(Request{Type: NoticeCard, Card: &c})
+	if co, ok := b.options.Fallback.(blackjack.CardObserver); ok {
+		co.ObserveCard(c)
+	}
+}
+
+func (b *Bot) ObserveShuffle(decks int) {
+	b.send(Request{Type: NoticeShuffle, Decks: decks})
+	if co, ok := b.options.Fallback.(blackjack.CardObserver); ok {
+		co.ObserveShuffle(decks)
+	}
+}
+
+func (b *Bot) DecideBet() int {
+	reply, err := b.ask(Request{Type: RequestBet})
+	if err != nil {
+		if b.options.Fallback != nil {
+			return b.options.Fallback.DecideBet()
+		}
+		return 0
+	}
+	return reply.Bet
+}
+
+func (b *Bot) DecideInsurance(hand blackjack.Hand) bool {
+	reply, err := b.ask(Request{Type: RequestInsurance, Hand: hand.Hand})
+	if err != nil {
+		return b.options.Fallback != nil && b.options.Fallback.DecideInsurance(hand)
+	}
+	return reply.Take
+}
+
+func (b *Bot) DecideSideBet(bet blackjack.SideBet) int {
+	reply, err := b.ask(Request{Type: RequestSideBet, SideBet: &bet})
+	if err != nil {
+		if sb, ok := b.options.Fallback.(blackjack.SideBetter); ok {
+			return sb.DecideSideBet(bet)
+		}
+		return 0
+	}
+	return reply.Bet
+}
+
+func (b *Bot) ChooseAction(hand blackjack.Hand, dealer deck.Card, legal []blackjack.Action) blackjack.Action {
+	reply, err := b.ask(Request{Type: RequestAction, Hand: hand.Hand, Dealer: &dealer, Legal: legal})
+	switch {
+	case err != nil:
+	case reply.Action == nil:
+		b.fail(fmt.Errorf("%w: reply has no action", ErrIllegal))
+		err = b.err
+	case !slices.Contains(legal, *reply.Action):
+		b.fail(fmt.Errorf("%w: %s is not one of %v", ErrIllegal, *reply.Action, legal))
+		err = b.err
+	}
+	if err != nil {
+		if b.options.Fallback != nil {
+			return blackjack.ChooseAction(b.options.Fallback, hand, dealer, legal)
+		}
+		return blackjack.Stand
+	}
+	return *reply.Action
+}
+
+// The decision hooks ask for an action between the one decided and the actions
+// that come after it.
+
+func (b *Bot) DecideSurrender(hand blackjack.Hand, dealer deck.Card) bool {
+	return b.chooses(hand, dealer, blackjack.Surrender)
+}
+
+func (b *Bot) DecideSplit(hand blackjack.Hand, dealer deck.Card) bool {
+	return b.chooses(hand, dealer, blackjack.Split)
+}
+
+func (b *Bot) DoubleDown(hand blackjack.Hand, dealer deck.Card) bool {
+	return b.chooses(hand, dealer, blackjack.DoubleDown)
+}
+
+func (b *Bot) DecideHit(hand blackjack.Hand, dealer deck.Card) bool {
+	return b.chooses(hand, dealer, blackjack.Hit)
+}
+
+func (b *Bot) chooses(hand blackjack.Hand, dealer deck.Card, action blackjack.Action) bool {
+	legal := []blackjack.Action{blackjack.Hit, blackjack.Stand, blackjack.DoubleDown, blackjack.Split, blackjack.Surrender}
+	switch action {
+	case blackjack.Split:
+		legal = legal[:4]
+	case blackjack.DoubleDown:
+		legal = legal[:3]
+	case blackjack.Hit:
+		legal = legal[:2]
+	}
+	return b.ChooseAction(hand, dealer, legal) == action
+}
+
+// Pool starts a bot for every AI asked for, to pass Pool.AI to blackjack.Simulate,
+// which uses a new AI for every chunk of rounds. The bots run until the pool is closed.
+type Pool struct {
+	newCmd  func() *exec.Cmd
+	options []OptionFunc
+	mu      sync.Mutex
+	bots    []*Bot
+}
+
+// NewPool returns a pool starting bots with the commands returned by newCmd.
+func NewPool(newCmd func() *exec.Cmd, options ...OptionFunc) *Pool {
+	return &Pool{newCmd: newCmd, options: options}
+}
+
+// AI starts a bot. A bot that can not be started plays as failed, its error is
+// returned by Close.
+func (p *Pool) AI() blackjack.AI {
+	b, err := Start(p.newCmd(), p.options...)
+	if err != nil {
+		b = newBot(p.options)
+		b.err = fmt.Errorf("bot: starting: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bots = append(p.bots, b)
+	return b
+}
+
+// Close stops every bot started and returns why bots failed.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for _, b := range p.bots {
+		errs = append(errs, b.Close())
+	}
+	p.bots = nil
+	return errors.Join(errs...)
+}
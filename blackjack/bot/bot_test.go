@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// TestHelperProcess is not a test, it is the bot run by helperCommand.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	runBot(os.Getenv("BOT_MODE"))
+	os.Exit(0)
+}
+
+// helperCommand runs the test binary as a bot behaving as the mode says:
+//
+//	play     bets 10 once seated, hits below 17 and declines insurance
+//	slow     plays but never replies to an action
+//	garbage  replies with a line that is not JSON
+//	illegal  always splits
+//	exit     exits at the first request
+func helperCommand(mode string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1", "BOT_MODE="+mode)
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+func runBot(mode string) {
+	in := bufio.NewScanner(os.Stdin)
+	in.Buffer(nil, 1<<20)
+	out := json.NewEncoder(os.Stdout)
+	seat := -1
+	for in.Scan() {
+		var r Request
+		if err := json.Unmarshal(in.Bytes(), &r); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if mode == "exit" {
+			os.Exit(3)
+		}
+
+		var reply Reply
+		switch r.Type {
+		case NoticeSeat:
+			seat = *r.Seat
+			continue
+		case RequestBet:
+			// Betting 10 shows the bot knows its seat and sees the table.
+			reply.Bet = 1
+			if r.State != nil {
+				if _, ok := r.State.Seat(seat); ok {
+					reply.Bet = 10
+				}
+			}
+		case RequestInsurance, RequestSideBet:
+		case RequestAction:
+			action, hand := blackjack.Stand, blackjack.Hand{Hand: r.Hand}
+			switch {
+			case mode == "slow":
+				time.Sleep(time.Minute)
+			case mode == "garbage":
+				fmt.Println("hit me")
+				continue
+			case mode == "illegal":
+				action = blackjack.Split
+			case slices.Contains(r.Legal, blackjack.Hit) && hand.Value() < 17:
+				action = blackjack.Hit
+			}
+			reply.Action = &action
+		default:
+			continue
+		}
+		out.Encode(reply)
+	}
+}
+
+func TestBot(t *testing.T) {
+	b, err := Start(helperCommand("play"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	var bets, actions int
+	e, err := blackjack.NewEngine(blackjack.DefaultRules(), blackjack.WithShoeSeed(1), blackjack.WithListener(func(ev blackjack.Event) {
+		switch ev := ev.(type) {
+		case blackjack.BetPlaced:
+			bets++
+			if ev.Amount != 10 {
+				t.Errorf("expected a bet of 10, got %d", ev.Amount)
+			}
+		case blackjack.PlayerActed:
+			actions++
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddPlayer("Bot", b); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := e.PlayRound(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if bets != 50 || actions == 0 {
+		t.Fatalf("expected the bot to bet 50 times and act, got %d bets and %d actions", bets, actions)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBotFails(t *testing.T) {
+	tests := []struct {
+		mode string
+		err  error
+	}{
+		{"slow", ErrTimeout},
+		{"illegal", ErrIllegal},
+		{"exit", ErrExited},
+		{"garbage", nil},
+	}
+	for _, test := range tests {
+		b, err := Start(helperCommand(test.mode), WithMoveTimeout(200*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hand := blackjack.Hand{Hand: cards(t, "8S 8H")}
+		legal := []blackjack.Action{blackjack.Hit, blackjack.Stand}
+		if a := b.ChooseAction(hand, cards(t, "TC")[0], legal); a != blackjack.Stand {
+			t.Errorf("%s: expected the bot to stand once failed, got %s", test.mode, a)
+		}
+		if b.Err() == nil || test.err != nil && !errors.Is(b.Err(), test.err) {
+			t.Errorf("%s: expected %v, got %v", test.mode, test.err, b.Err())
+		}
+		// A failed bot sits out.
+		if bet := b.DecideBet(); bet != 0 {
+			t.Errorf("%s: expected a failed bot to sit out, got a bet of %d", test.mode, bet)
+		}
+		if err := b.Close(); err == nil {
+			t.Errorf("%s: expected Close to report the failure", test.mode)
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	rules := blackjack.DefaultRules()
+	b, err := Start(helperCommand("exit"), WithFallback(blackjack.NewBasicStrategy(rules, 5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	hand := blackjack.Hand{Hand: cards(t, "8S 8H")}
+	legal := []blackjack.Action{blackjack.Hit, blackjack.Stand, blackjack.DoubleDown, blackjack.Split}
+	if a := b.ChooseAction(hand, cards(t, "6C")[0], legal); a != blackjack.Split {
+		t.Fatalf("expected the fallback to split 8s, got %s", a)
+	}
+	if bet := b.DecideBet(); bet != 5 {
+		t.Fatalf("expected the fallback's bet of 5, got %d", bet)
+	}
+}
+
+func TestPool(t *testing.T) {
+	rules := blackjack.DefaultRules()
+	p := NewPool(func() *exec.Cmd { return helperCommand("play") })
+	defer p.Close()
+
+	r, err := blackjack.Simulate(rules, p.AI, 500, blackjack.WithSeed(1), blackjack.WithWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rounds != 500 || r.TotalBet < 5000 {
+		t.Fatalf("expected 500 rounds of bets of 10, got\n%s", r)
+	}
+
+	entrants := []blackjack.Entrant{
+		{Name: "Bot", AI: p.AI()},
+		{Name: "Basic", AI: blackjack.NewBasicStrategy(rules, 10)},
+		{Name: "Other bot", AI: p.AI()},
+	}
+	result, err := blackjack.RunTournament(rules, entrants, blackjack.WithHandsPerRound(10), blackjack.WithTournamentSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Leaderboard) != 3 {
+		t.Fatalf("expected every entrant on the leaderboard, got\n%s", result)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func cards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	cards, err := deck.ParseDeck(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
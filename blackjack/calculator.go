@@ -0,0 +1,488 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Composition counts the cards left in a shoe by blackjack value, index 1 for aces
+// and 10 for tens and faces. Index 0 is unused.
+type Composition [11]int
+
+// NewComposition returns the composition of a full shoe of the given number of decks.
+func NewComposition(decks int) Composition {
+	var c Composition
+	for v := 1; v <= 9; v++ {
+		c[v] = 4 * decks
+	}
+	c[10] = 16 * decks
+	return c
+}
+
+// CompositionOf counts the cards.
+func CompositionOf(cards []deck.Card) Composition {
+	var c Composition
+	for _, card := range cards {
+		c[cardValue(card)]++
+	}
+	return c
+}
+
+// Remove returns the composition without the cards. It fails when a card is not left.
+func (c Composition) Remove(cards ...deck.Card) (Composition, error) {
+	for _, card := range cards {
+		v := cardValue(card)
+		if c[v] == 0 {
+			return c, fmt.Errorf("blackjack: no %s left in the shoe", card.Type)
+		}
+		c[v]--
+	}
+	return c, nil
+}
+
+// Cards returns the number of cards.
+func (c Composition) Cards() int {
+	var n int
+	for _, count := range c[1:] {
+		n += count
+	}
+	return n
+}
+
+// Expectation holds the expected value of every action on a hand per unit of its
+// bet, given the dealer does not have a blackjack when the up card lets the dealer
+// peek for one. Split is an approximation: the split hands are not split again and
+// each is valued as if the other drew no cards. Split is NaN for a hand that is not
+// a pair.
+type Expectation struct {
+	Stand     float64 `json:"stand"`
+	Hit       float64 `json:"hit"`
+	Double    float64 `json:"double"`
+	Split     float64 `json:"split"`
+	Surrender float64 `json:"surrender"`
+}
+
+// Of returns the expected value of an action.
+func (x Expectation) Of(action Action) float64 {
+	switch action {
+	case Hit:
+		return x.Hit
+	case DoubleDown:
+		return x.Double
+	case Split:
+		return x.Split
+	case Surrender:
+		return x.Surrender
+	}
+	return x.Stand
+}
+
+// Best returns the legal action with the highest expected value, standing when none is legal.
+func (x Expectation) Best(legal []Action) Action {
+	best := Stand
+	for _, a := range legal {
+		if x.Of(a) > x.Of(best) || !slices.Contains(legal, best) {
+			best = a
+		}
+	}
+	return best
+}
+
+// Calculator computes the expected values of the actions on a hand from the cards
+// left in the shoe. Standing, hitting, doubling and surrendering are valued exactly,
+// drawing to a hand is played on optimally for the cards left after every draw.
+// Splitting is approximated, see Expectation. Results are cached, so a calculator reused for many hands from
+// similar shoes gets faster. A Calculator is not safe for concurrent use.
+type Calculator struct {
+	rules  Rules
+	hits   [22][2]bool
+	dealer map[dealerKey]*[23]float64
+	hit    map[playerKey]float64
+}
+
+type dealerKey struct {
+	shoe Composition
+	up   int
+}
+
+type playerKey struct {
+	shoe Composition
+	up   int
+	hand handState
+}
+
+// handState is a player total, soft when an ace counts as 11.
+type handState struct {
+	total int
+	soft  bool
+}
+
+// drawTypes are the cards drawn for each value.
+var drawTypes = [11]deck.Type{1: deck.ACE, 2: deck.TWO, 3: deck.THREE, 4: deck.FOUR, 5: deck.FIVE, 6: deck.SIX, 7: deck.SEVEN, 8: deck.EIGHT, 9: deck.NINE, 10: deck.TEN}
+
+// add returns the hand after drawing a card of value v, aces as 1.
+func (s handState) add(v int) handState {
+	if v == 1 && s.total+11 <= 21 {
+		return handState{s.total + 11, true}
+	}
+	s.total += v
+	if s.total > 21 && s.soft {
+		s.total -= 10
+		s.soft = false
+	}
+	return s
+}
+
+func stateOf(hand Hand) handState {
+	return handState{hand.Value(), hand.IsSoft()}
+}
+
+// NewCalculator returns a calculator for the rules.
+func NewCalculator(rules Rules) (*Calculator, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	c := &Calculator{rules: rules, dealer: map[dealerKey]*[23]float64{}, hit: map[playerKey]float64{}}
+
+	// The dealer strategy decides on a hand, any hand with the total will do.
+	strategy := rules.DealerStrategy()
+	for total := 4; total <= 21; total++ {
+		c.hits[total][0] = strategy.DecideHit(handOf(handState{total: total}))
+	}
+	for total := 12; total <= 21; total++ {
+		c.hits[total][1] = strategy.DecideHit(handOf(handState{total: total, soft: true}))
+	}
+	return c, nil
+}
+
+// handOf returns a hand with the total, made of as few cards as possible.
+func handOf(s handState) Hand {
+	var h Hand
+	total := s.total
+	if s.soft {
+		h.addCard(deck.Card{Suit: deck.SPADE, Type: deck.ACE})
+		total -= 11
+		if total == 1 {
+			h.addCard(deck.Card{Suit: deck.HEART, Type: deck.ACE})
+			return h
+		}
+	}
+	for total > 0 {
+		v := min(total, 10)
+		if total-v == 1 {
+			v--
+		}
+		h.addCard(deck.Card{Suit: deck.SPADE, Type: drawTypes[v]})
+		total -= v
+	}
+	return h
+}
+
+// Evaluate returns the expected values of the actions on the hand against the dealer's
+// up card. The shoe holds the cards left to draw from, which includes the dealer's
+// hole card but not the hand or the up card. Doubling and surrendering are valued
+// even when the rules or the number of cards do not allow them.
+func (c *Calculator) Evaluate(hand Hand, dealer deck.Card, shoe Composition) (Expectation, error) {
+	if len(hand.Hand) == 0 {
+		return Expectation{}, fmt.Errorf("blackjack: no cards to evaluate")
+	}
+	if shoe.Cards() == 0 {
+		return Expectation{}, fmt.Errorf("blackjack: no cards left in the shoe")
+	}
+	up := cardValue(dealer)
+	s := stateOf(hand)
+	noBlackjack := c.noBlackjack(shoe, up)
+
+	x := Expectation{
+		Stand:     c.stand(shoe, up, s) / noBlackjack,
+		Hit:       c.hitValue(shoe, up, s) / noBlackjack,
+		Double:    c.double(shoe, up, s) / noBlackjack,
+		Split:     math.NaN(),
+		Surrender: -0.5,
+	}
+	if hand.IsPair() {
+		x.Split = c.approxSplit(shoe, up, cardValue(hand.Hand[0])) / noBlackjack
+	}
+	return x, nil
+}
+
+// Chart returns the strategy with the highest expected value for every hand drawn
+// from a full shoe, for each total played with a typical two card hand. Pairs are
+// only split when splitting beats every other move. The columns are computed in
+// parallel, each with a cache of its own.
+func (c *Calculator) Chart() (*Chart, error) {
+	chart := &Chart{}
+	ups := chartUpCards()
+	errs := make([]error, len(ups))
+
+	var wg sync.WaitGroup
+	for col, up := range ups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			column := &Calculator{rules: c.rules, hits: c.hits, dealer: map[dealerKey]*[23]float64{}, hit: map[playerKey]float64{}}
+			errs[col] = column.chartColumn(chart, col, up)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return chart, nil
+}
+
+// chartColumn fills the column of the chart for the up card.
+func (c *Calculator) chartColumn(chart *Chart, col int, up deck.Card) error {
+	full := NewComposition(c.rules.Decks)
+	evaluate := func(hand Hand) (Expectation, error) {
+		shoe, err := full.Remove(append(slices.Clone(hand.Hand), up)...)
+		if err != nil {
+			return Expectation{}, err
+		}
+		return c.Evaluate(hand, up, shoe)
+	}
+
+	for total := 4; total <= 21; total++ {
+		hand := twoCardHand(total)
+		x, err := evaluate(hand)
+		if err != nil {
+			return err
+		}
+		chart.Hard[total][col] = c.move(hand, x)
+	}
+	for total := 12; total <= 21; total++ {
+		hand := handOf(handState{total: total, soft: true})
+		x, err := evaluate(hand)
+		if err != nil {
+			return err
+		}
+		chart.Soft[total][col] = c.move(hand, x)
+	}
+	for v := 2; v <= 11; v++ {
+		t := drawTypes[min(v, 10)]
+		if v == 11 {
+			t = deck.ACE
+		}
+		pair := Hand{Hand: []deck.Card{{Suit: deck.SPADE, Type: t}, {Suit: deck.HEART, Type: t}}}
+		x, err := evaluate(pair)
+		if err != nil {
+			return err
+		}
+		m := MoveNone
+		if x.Split > x.Of(c.bestTotalAction(pair, x, false)) {
+			m = MoveSplit
+			if x.Surrender > x.Split && c.bestTotalAction(pair, x, true) == Surrender {
+				m = MoveSurrenderOrSplit
+			}
+		}
+		chart.Pairs[v][col] = m
+	}
+	return nil
+}
+
+// chartUpCards returns an up card for every column of a chart.
+func chartUpCards() []deck.Card {
+	cards := make([]deck.Card, len(upCards))
+	for v := 2; v <= 10; v++ {
+		cards[v-2] = deck.Card{Suit: deck.CLUB, Type: drawTypes[v]}
+	}
+	cards[len(upCards)-1] = deck.Card{Suit: deck.CLUB, Type: deck.ACE}
+	return cards
+}
+
+// twoCardHand returns a hand of two different cards with the hard total when there is
+// one, e.g 10 and 6 for 16.
+func twoCardHand(total int) Hand {
+	hi := min(total-2, 10)
+	lo := total - hi
+	if lo == hi && lo > 2 {
+		hi, lo = hi+1, lo-1
+	}
+	if lo < 2 || hi > 10 || lo > 10 {
+		return handOf(handState{total: total})
+	}
+	return Hand{Hand: []deck.Card{{Suit: deck.SPADE, Type: drawTypes[hi]}, {Suit: deck.HEART, Type: drawTypes[lo]}}}
+}
+
+// bestTotalAction returns the best action on the hand without splitting, and
+// without surrendering unless surrender is set.
+func (c *Calculator) bestTotalAction(hand Hand, x Expectation, surrender bool) Action {
+	legal := []Action{Hit, Stand}
+	if len(hand.Hand) == 2 && c.rules.canDouble(hand.Value()) {
+		legal = append(legal, DoubleDown)
+	}
+	if surrender && len(hand.Hand) == 2 && c.rules.Surrender {
+		legal = append(legal, Surrender)
+	}
+	return x.Best(legal)
+}
+
+// move returns the chart entry for the hand, without splitting.
+func (c *Calculator) move(hand Hand, x Expectation) Move {
+	switch c.bestTotalAction(hand, x, true) {
+	case DoubleDown:
+		if x.Hit >= x.Stand {
+			return MoveDoubleOrHit
+		}
+		return MoveDoubleOrStand
+	case Surrender:
+		if x.Hit >= x.Stand {
+			return MoveSurrenderOrHit
+		}
+		return MoveSurrenderOrStand
+	case Hit:
+		return MoveHit
+	}
+	return MoveStand
+}
+
+// Values below are not divided by the odds of the dealer not having a blackjack. The
+// hole card can be drawn last without changing the odds, so comparing values for the
+// same shoe gives the same decisions.
+
+// blackjackCard returns the value of the hole card making a blackjack with the up card, 0 for none.
+func blackjackCard(up int) int {
+	switch up {
+	case 1:
+		return 10
+	case 10:
+		return 1
+	}
+	return 0
+}
+
+// noBlackjack returns the odds of the dealer not having a blackjack.
+func (c *Calculator) noBlackjack(shoe Composition, up int) float64 {
+	if v := blackjackCard(up); v != 0 {
+		return 1 - float64(shoe[v])/float64(shoe.Cards())
+	}
+	return 1
+}
+
+// dealerOdds returns the odds of the dealer's final totals, 22 standing for a bust.
+func (c *Calculator) dealerOdds(shoe Composition, up int) *[23]float64 {
+	key := dealerKey{shoe, up}
+	if odds, ok := c.dealer[key]; ok {
+		return odds
+	}
+	odds := new([23]float64)
+	var start handState
+	start = start.add(up)
+	c.dealerDraw(&shoe, shoe.Cards(), up, start, 1, 1, odds)
+	c.dealer[key] = odds
+	return odds
+}
+
+// dealerDraw adds the odds of the totals the dealer reaches from the hand, reached with
+// odds p, drawing from the n cards of the shoe.
+func (c *Calculator) dealerDraw(shoe *Composition, n, up int, s handState, cards int, p float64, odds *[23]float64) {
+	if s.total > 21 {
+		odds[22] += p
+		return
+	}
+	if n == 0 || (cards >= 2 && !c.hits[s.total][b2i(s.soft)]) {
+		odds[s.total] += p
+		return
+	}
+	excluded := 0
+	if cards == 1 {
+		excluded = blackjackCard(up)
+	}
+	for v := 1; v <= 10; v++ {
+		if shoe[v] == 0 || v == excluded {
+			continue
+		}
+		q := p * float64(shoe[v]) / float64(n)
+		shoe[v]--
+		c.dealerDraw(shoe, n-1, up, s.add(v), cards+1, q, odds)
+		shoe[v]++
+	}
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (c *Calculator) stand(shoe Composition, up int, s handState) float64 {
+	if s.total > 21 {
+		return -c.noBlackjack(shoe, up)
+	}
+	odds := c.dealerOdds(shoe, up)
+	ev := odds[22]
+	for total, p := range odds[:22] {
+		switch {
+		case total < s.total:
+			ev += p
+		case total > s.total:
+			ev -= p
+		}
+	}
+	return ev
+}
+
+// draw returns the value of drawing one card to the hand, then playing on with play.
+func (c *Calculator) draw(shoe Composition, up int, s handState, play func(Composition, handState) float64) float64 {
+	n := shoe.Cards()
+	if n == 0 {
+		return c.stand(shoe, up, s)
+	}
+	var ev float64
+	for v := 1; v <= 10; v++ {
+		if shoe[v] == 0 {
+			continue
+		}
+		q := float64(shoe[v]) / float64(n)
+		next := shoe
+		next[v]--
+		ev += q * play(next, s.add(v))
+	}
+	return ev
+}
+
+// hitValue returns the value of hitting and then playing on by hitting or standing.
+func (c *Calculator) hitValue(shoe Composition, up int, s handState) float64 {
+	key := playerKey{shoe, up, s}
+	if ev, ok := c.hit[key]; ok {
+		return ev
+	}
+	ev := c.draw(shoe, up, s, func(next Composition, s handState) float64 {
+		if s.total > 21 {
+			return c.stand(next, up, s)
+		}
+		return math.Max(c.stand(next, up, s), c.hitValue(next, up, s))
+	})
+	c.hit[key] = ev
+	return ev
+}
+
+func (c *Calculator) double(shoe Composition, up int, s handState) float64 {
+	return 2 * c.draw(shoe, up, s, func(next Composition, s handState) float64 {
+		return c.stand(next, up, s)
+	})
+}
+
+// approxSplit returns the value of splitting a pair of cards of value v, as twice the
+// value of one split hand drawing from the shoe.
+func (c *Calculator) approxSplit(shoe Composition, up, v int) float64 {
+	var start handState
+	start = start.add(v)
+	return 2 * c.draw(shoe, up, start, func(next Composition, s handState) float64 {
+		if v == 1 && !c.rules.HitSplitAces {
+			return c.stand(next, up, s)
+		}
+		best := math.Max(c.stand(next, up, s), c.hitValue(next, up, s))
+		if c.rules.DoubleAfterSplit && c.rules.canDouble(s.total) {
+			best = math.Max(best, c.double(next, up, s))
+		}
+		return best
+	})
+}
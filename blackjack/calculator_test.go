@@ -0,0 +1,102 @@
+package blackjack
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func TestCalculator(t *testing.T) {
+	rules := DefaultRules()
+	c, err := NewCalculator(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A full shoe is close to an infinite deck, whose values are well known.
+	for _, test := range []struct {
+		hand   Hand
+		dealer deck.Type
+		action Action
+		ev     float64
+	}{
+		{hand(deck.TEN, deck.SIX), deck.TEN, Stand, -0.5404},
+		{hand(deck.TEN, deck.SIX), deck.TEN, Hit, -0.5398},
+		{hand(deck.FIVE, deck.SIX), deck.SIX, DoubleDown, 0.6647},
+		{hand(deck.FIVE, deck.SIX), deck.SIX, Hit, 0.3323},
+		{hand(deck.ACE, deck.SEVEN), deck.NINE, Hit, -0.1007},
+		{hand(deck.ACE, deck.SEVEN), deck.NINE, Stand, -0.1832},
+		{hand(deck.EIGHT, deck.EIGHT), deck.ACE, Split, -0.5269},
+		{hand(deck.EIGHT, deck.EIGHT), deck.ACE, Stand, -0.5987},
+	} {
+		up := deck.Card{Type: test.dealer}
+		shoe, err := NewComposition(rules.Decks).Remove(append(slices.Clone(test.hand.Hand), up)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, err := c.Evaluate(test.hand, up, shoe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(x.Of(test.action)-test.ev) > 0.02 {
+			t.Errorf("expected %s on %v vs %s to be worth about %.4f, got %.4f", test.action, test.hand.Hand, test.dealer, test.ev, x.Of(test.action))
+		}
+	}
+
+	// Standing on 16 against a 10 is best when the shoe has only tens left for the dealer to bust on.
+	shoe := CompositionOf(hand(deck.TEN, deck.TEN, deck.TEN, deck.TEN, deck.SIX, deck.SIX).Hand)
+	x, err := c.Evaluate(hand(deck.TEN, deck.SIX), deck.Card{Type: deck.TEN}, shoe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Best([]Action{Hit, Stand, Surrender}) != Stand || !math.IsNaN(x.Split) {
+		t.Fatalf("unexpected expectation %+v", x)
+	}
+
+	if _, err := NewComposition(1).Remove(hand(deck.ACE, deck.ACE, deck.ACE, deck.ACE, deck.ACE).Hand...); err == nil {
+		t.Fatal("expected removing a fifth ace from a deck to fail")
+	}
+}
+
+func TestCalculatorChart(t *testing.T) {
+	if testing.Short() {
+		t.Skip("computing a chart takes a few seconds")
+	}
+	rules := DefaultRules()
+	c, err := NewCalculator(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact, err := c.Chart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Basic strategy is the best play for every typical hand from a full shoe.
+	basic := BasicStrategyChart(rules)
+	legal := []Action{Hit, Stand, DoubleDown, Split, Surrender}
+	var differences int
+	for _, up := range chartUpCards() {
+		var hands []Hand
+		for total := 4; total <= 21; total++ {
+			hands = append(hands, twoCardHand(total))
+		}
+		for total := 13; total <= 21; total++ {
+			hands = append(hands, handOf(handState{total: total, soft: true}))
+		}
+		for v := 1; v <= 10; v++ {
+			hands = append(hands, Hand{Hand: []deck.Card{{Type: drawTypes[v]}, {Type: drawTypes[v]}}})
+		}
+		for _, h := range hands {
+			if a, b := exact.Action(h, up, legal, true), basic.Action(h, up, legal, true); a != b {
+				t.Logf("%v vs %s: exact %s, basic strategy %s", h.Hand, up.Type, a, b)
+				differences++
+			}
+		}
+	}
+	if differences > 0 {
+		t.Fatalf("expected the exact chart to match basic strategy, got %d differences", differences)
+	}
+}
@@ -0,0 +1,248 @@
+package blackjack
+
+import (
+	"math"
+	"slices"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// CountSystem assigns a tag to every card value. Tags is indexed by the blackjack
+// value of a card, 1 for aces and 10 for tens and faces.
+type CountSystem struct {
+	Name string
+	Tags [11]int
+}
+
+var (
+	// HiLo counts 2 to 6 as +1 and tens and aces as -1.
+	HiLo = CountSystem{Name: "Hi-Lo", Tags: [11]int{1: -1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 10: -1}}
+	// KO is Hi-Lo with 7 counted as +1, which makes it unbalanced.
+	KO = CountSystem{Name: "KO", Tags: [11]int{1: -1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 10: -1}}
+	// OmegaII is a level two count that leaves aces out.
+	OmegaII = CountSystem{Name: "Omega II", Tags: [11]int{2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 1, 9: -1, 10: -2}}
+)
+
+// Tag returns the tag of a card.
+func (s CountSystem) Tag(c deck.Card) int {
+	if c.Type == deck.ACE {
+		return s.Tags[1]
+	}
+	return s.Tags[cardValue(c)]
+}
+
+// Imbalance returns the running count after counting a full deck, 0 for a balanced system.
+func (s CountSystem) Imbalance() int {
+	sum := s.Tags[10] * 16
+	for v := 1; v <= 9; v++ {
+		sum += s.Tags[v] * 4
+	}
+	return sum
+}
+
+// Counter keeps the count of the cards seen since the last shuffle. It implements
+// CardObserver so an AI can embed it to be told about the cards.
+type Counter struct {
+	system  CountSystem
+	decks   int
+	running int
+	seen    int
+}
+
+// NewCounter returns a counter for a freshly shuffled shoe of the given number of decks.
+// Unbalanced systems start at the initial running count, -Imbalance() per deck after the first.
+func NewCounter(system CountSystem, decks int) *Counter {
+	c := &Counter{system: system}
+	c.ObserveShuffle(decks)
+	return c
+}
+
+func (c *Counter) ObserveCard(card deck.Card) {
+	c.running += c.system.Tag(card)
+	c.seen++
+}
+
+func (c *Counter) ObserveShuffle(decks int) {
+	c.decks = decks
+	c.seen = 0
+	c.running = -c.system.Imbalance() * (decks - 1)
+}
+
+// System returns the counting system.
+func (c *Counter) System() CountSystem {
+	return c.system
+}
+
+// RunningCount returns the sum of the tags of the cards seen.
+func (c *Counter) RunningCount() int {
+	return c.running
+}
+
+// DecksRemaining estimates the decks left in the shoe from the cards seen,
+// never less than a quarter deck.
+func (c *Counter) DecksRemaining() float64 {
+	return math.Max(float64(c.decks*52-c.seen)/52, 0.25)
+}
+
+// TrueCount returns the running count per deck remaining. For an unbalanced system
+// the count a neutral shoe would have reached is taken off first, so the true count
+// reads the same way as for a balanced system.
+func (c *Counter) TrueCount() float64 {
+	imbalance := c.system.Imbalance()
+	expected := float64(-imbalance*(c.decks-1)) + float64(imbalance*c.seen)/52
+	return (float64(c.running) - expected) / c.DecksRemaining()
+}
+
+// Deviation is an index play: when the true count reaches Index the hand is played
+// with Action instead of by the chart, or below Index when Below is set. The hand
+// matches on its hard Total and the dealer's up card value, 11 for an ace. A Pair
+// deviation only matches a pair that can be split. An Insurance deviation takes
+// insurance instead, and only Index and Below are used.
+type Deviation struct {
+	Total     int
+	Pair      bool
+	Dealer    int
+	Index     float64
+	Below     bool
+	Action    Action
+	Insurance bool
+}
+
+// applies reports whether the deviation is played at the true count.
+func (d Deviation) applies(count float64) bool {
+	if d.Below {
+		return count < d.Index
+	}
+	return count >= d.Index
+}
+
+// Illustrious18 are the most valuable index plays for Hi-Lo in a shoe game. Their
+// indices do not fit other counting systems.
+var Illustrious18 = []Deviation{
+	{Insurance: true, Index: 3},
+	{Total: 16, Dealer: 10, Index: 0, Action: Stand},
+	{Total: 15, Dealer: 10, Index: 4, Action: Stand},
+	{Total: 20, Pair: true, Dealer: 5, Index: 5, Action: Split},
+	{Total: 20, Pair: true, Dealer: 6, Index: 4, Action: Split},
+	{Total: 10, Dealer: 10, Index: 4, Action: DoubleDown},
+	{Total: 12, Dealer: 3, Index: 2, Action: Stand},
+	{Total: 12, Dealer: 2, Index: 3, Action: Stand},
+	{Total: 11, Dealer: 11, Index: 1, Action: DoubleDown},
+	{Total: 9, Dealer: 2, Index: 1, Action: DoubleDown},
+	{Total: 10, Dealer: 11, Index: 4, Action: DoubleDown},
+	{Total: 9, Dealer: 7, Index: 3, Action: DoubleDown},
+	{Total: 16, Dealer: 9, Index: 5, Action: Stand},
+	{Total: 13, Dealer: 2, Index: -1, Below: true, Action: Hit},
+	{Total: 12, Dealer: 4, Index: 0, Below: true, Action: Hit},
+	{Total: 12, Dealer: 5, Index: -2, Below: true, Action: Hit},
+	{Total: 12, Dealer: 6, Index: -1, Below: true, Action: Hit},
+	{Total: 13, Dealer: 3, Index: -2, Below: true, Action: Hit},
+}
+
+// RampStep is the bet made from a true count on, see BetRamp.
+type RampStep struct {
+	Count float64
+	Bet   int
+}
+
+// BetRamp sizes bets by the true count. Steps are sorted by Count, the bet is the one
+// of the last step the count reaches, or of the first step when it reaches none.
+type BetRamp []RampStep
+
+// Spread returns a ramp betting one unit below a true count of 2, then as many
+// units as the true count up to spread units.
+func Spread(unit, spread int) BetRamp {
+	ramp := BetRamp{{Count: math.Inf(-1), Bet: unit}}
+	for units := 2; units <= spread; units++ {
+		ramp = append(ramp, RampStep{Count: float64(units), Bet: units * unit})
+	}
+	return ramp
+}
+
+// Bet returns the bet for the true count.
+func (r BetRamp) Bet(count float64) int {
+	if len(r) == 0 {
+		return 0
+	}
+	bet := r[0].Bet
+	for _, step := range r {
+		if count < step.Count {
+			break
+		}
+		bet = step.Bet
+	}
+	return bet
+}
+
+// CountingAI plays the chart, deviating from it with index plays, and bets
+// following a ramp. Surrendering is never given up for a deviation, and a pair the
+// chart splits is only played differently by a Pair deviation.
+type CountingAI struct {
+	*Counter
+	Chart      *Chart
+	Rules      Rules
+	Ramp       BetRamp
+	Deviations []Deviation
+}
+
+// NewCountingAI returns an AI counting with the system, playing basic strategy for
+// the rules with the deviations.
+func NewCountingAI(system CountSystem, rules Rules, ramp BetRamp, deviations []Deviation) *CountingAI {
+	return &CountingAI{
+		Counter:    NewCounter(system, rules.Decks),
+		Chart:      BasicStrategyChart(rules),
+		Rules:      rules,
+		Ramp:       ramp,
+		Deviations: deviations,
+	}
+}
+
+func (ai *CountingAI) DecideBet() int {
+	return ai.Ramp.Bet(ai.TrueCount())
+}
+
+func (ai *CountingAI) DecideInsurance(hand Hand) bool {
+	count := ai.TrueCount()
+	for _, d := range ai.Deviations {
+		if d.Insurance {
+			return d.applies(count)
+		}
+	}
+	return false
+}
+
+func (ai *CountingAI) ChooseAction(hand Hand, dealer deck.Card, legal []Action) Action {
+	action := ai.Chart.Action(hand, dealer, legal, ai.Rules.DoubleAfterSplit)
+	if action == Surrender || hand.IsSoft() {
+		return action
+	}
+
+	count := ai.TrueCount()
+	up := upCardIndex(dealer) + 2
+	pair := hand.IsPair() && slices.Contains(legal, Split)
+	for _, d := range ai.Deviations {
+		if d.Insurance || d.Total != hand.Value() || d.Dealer != up || (d.Pair && !pair) || (!d.Pair && action == Split) {
+			continue
+		}
+		if d.applies(count) && slices.Contains(legal, d.Action) {
+			return d.Action
+		}
+	}
+	return action
+}
+
+func (ai *CountingAI) DecideSurrender(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Surrender)
+}
+
+func (ai *CountingAI) DecideSplit(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Split)
+}
+
+func (ai *CountingAI) DoubleDown(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, DoubleDown)
+}
+
+func (ai *CountingAI) DecideHit(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Hit)
+}
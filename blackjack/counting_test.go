@@ -0,0 +1,142 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// observingAI records the cards it is shown.
+type observingAI struct {
+	scriptedAI
+	cards    []deck.Card
+	shuffles int
+}
+
+func (ai *observingAI) ObserveCard(c deck.Card) { ai.cards = append(ai.cards, c) }
+func (ai *observingAI) ObserveShuffle(int)      { ai.shuffles++ }
+
+func TestEngineShowsRevealedCards(t *testing.T) {
+	ai := &observingAI{scriptedAI: scriptedAI{double: true, bet: 1}}
+	e := stackedEngine(t, "Ts 5s 7c 6h 9d")
+	e.AddPlayer("AI", ai)
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hole card is shown once it is flipped, after the player's double.
+	if got := deck.FormatDeck(ai.cards); got != "Ts 5s 6h 9d 7c" {
+		t.Fatalf("expected cards Ts 5s 6h 9d 7c, got %s", got)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	full := deck.NewDeck()
+	for _, system := range []CountSystem{HiLo, KO, OmegaII} {
+		t.Run(system.Name, func(t *testing.T) {
+			c := NewCounter(system, 2)
+			for _, card := range full {
+				c.ObserveCard(card)
+			}
+			// After a neutral deck the true count is 0 for every system.
+			if tc := c.TrueCount(); tc != 0 {
+				t.Fatalf("expected true count 0 after a full deck, got %v", tc)
+			}
+			// Unbalanced systems start below 0 and end a full deck later at 0.
+			if system.Imbalance() != 0 && c.RunningCount() != 0 {
+				t.Fatalf("expected running count 0 after one of two decks, got %d", c.RunningCount())
+			}
+		})
+	}
+
+	if KO.Imbalance() != 4 || HiLo.Imbalance() != 0 || OmegaII.Imbalance() != 0 {
+		t.Fatal("expected KO to be the only unbalanced system")
+	}
+
+	c := NewCounter(HiLo, 6)
+	for range 26 {
+		c.ObserveCard(deck.Card{Suit: deck.SPADE, Type: deck.FIVE})
+	}
+	// +26 with 5.5 decks left.
+	if tc := c.TrueCount(); tc < 4.72 || tc > 4.73 {
+		t.Fatalf("expected true count 4.73, got %v", tc)
+	}
+	c.ObserveShuffle(6)
+	if c.RunningCount() != 0 || c.TrueCount() != 0 {
+		t.Fatal("expected the count to reset on a shuffle")
+	}
+}
+
+func TestBetRamp(t *testing.T) {
+	ramp := Spread(5, 8)
+	for _, tt := range []struct {
+		count float64
+		bet   int
+	}{{-3, 5}, {1.9, 5}, {2, 10}, {4.5, 20}, {12, 40}} {
+		if got := ramp.Bet(tt.count); got != tt.bet {
+			t.Errorf("expected bet %d at true count %v, got %d", tt.bet, tt.count, got)
+		}
+	}
+}
+
+func TestIndexPlays(t *testing.T) {
+	ai := NewCountingAI(HiLo, DefaultRules(), Spread(1, 8), Illustrious18)
+	ten := deck.Card{Suit: deck.HEART, Type: deck.TEN}
+	five := deck.Card{Suit: deck.HEART, Type: deck.FIVE}
+	legal := []Action{Hit, Stand, DoubleDown, Split}
+
+	if got := ai.ChooseAction(hand(deck.KING, deck.KING), five, legal); got != Stand {
+		t.Fatalf("expected to stand on tens at a neutral count, got %v", got)
+	}
+	if ai.DecideInsurance(hand(deck.NINE, deck.EIGHT)) {
+		t.Fatal("expected no insurance at a neutral count")
+	}
+
+	for range 10 {
+		ai.ObserveCard(ten)
+	}
+	if got := ai.ChooseAction(hand(deck.NINE, deck.SIX, deck.ACE), ten, []Action{Hit, Stand}); got != Hit {
+		t.Fatalf("expected to hit 16 against a ten at a negative count, got %v", got)
+	}
+
+	for range 6 * 52 / 2 {
+		ai.ObserveCard(deck.Card{Suit: deck.SPADE, Type: deck.TWO})
+	}
+	if got := ai.ChooseAction(hand(deck.KING, deck.KING), five, legal); got != Split {
+		t.Fatalf("expected to split tens against a five at a high count, got %v", got)
+	}
+	if got := ai.ChooseAction(hand(deck.NINE, deck.SIX, deck.ACE), ten, []Action{Hit, Stand}); got != Stand {
+		t.Fatalf("expected to stand on 16 against a ten at a high count, got %v", got)
+	}
+	// Surrender is kept at any count.
+	if got := ai.ChooseAction(hand(deck.TEN, deck.SIX), ten, []Action{Hit, Stand, DoubleDown, Surrender}); got != Surrender {
+		t.Fatalf("expected to surrender 16 against a ten, got %v", got)
+	}
+	if !ai.DecideInsurance(hand(deck.NINE, deck.EIGHT)) {
+		t.Fatal("expected insurance at a high count")
+	}
+	if bet := ai.DecideBet(); bet != 8 {
+		t.Fatalf("expected the top bet at a high count, got %d", bet)
+	}
+}
+
+func TestSimulateCounting(t *testing.T) {
+	rules := DefaultRules()
+	flat := func() AI { return NewBasicStrategy(rules, 1) }
+	counting := func() AI { return NewCountingAI(HiLo, rules, Spread(1, 12), Illustrious18) }
+
+	basic, err := Simulate(rules, flat, 300000, WithSeed(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counted, err := Simulate(rules, counting, 300000, WithSeed(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A 1 to 12 spread turns the edge over to the player.
+	if counted.HouseEdge >= basic.HouseEdge || counted.HouseEdge > 0 {
+		t.Fatalf("expected counting to beat the house, got %.3f%% flat and %.3f%% counting",
+			100*basic.HouseEdge, 100*counted.HouseEdge)
+	}
+}
@@ -0,0 +1,1036 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Phase is the stage of a round the Engine is waiting in.
+type Phase uint8
+
+const (
+	// PhaseIdle is the phase before the first round and after a round is settled.
+	PhaseIdle Phase = iota
+	// PhaseBetting waits for bets until Deal is called.
+	PhaseBetting
+	// PhaseInsurance waits for every player to accept or decline insurance.
+	PhaseInsurance
+	// PhasePlayerTurn waits for the player whose turn it is to act.
+	PhasePlayerTurn
+)
+
+var phaseNames = [...]string{"idle", "betting", "insurance", "player turn"}
+
+func (p Phase) String() string {
+	if int(p) < len(phaseNames) {
+		return phaseNames[p]
+	}
+	return "unknown"
+}
+
+func (p Phase) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Phase) UnmarshalText(text []byte) error {
+	i := slices.Index(phaseNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("blackjack: unknown phase %q", text)
+	}
+	*p = Phase(i)
+	return nil
+}
+
+// Action is a decision a player makes on a hand.
+type Action uint8
+
+const (
+	Hit Action = iota
+	Stand
+	DoubleDown
+	Split
+	Surrender
+)
+
+var actionNames = [...]string{"hit", "stand", "double", "split", "surrender"}
+
+func (a Action) String() string {
+	if int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return "unknown"
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	i := slices.Index(actionNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("blackjack: unknown action %q", text)
+	}
+	*a = Action(i)
+	return nil
+}
+
+var (
+	ErrWrongPhase     = errors.New("blackjack: not allowed in this phase of the round")
+	ErrNotYourTurn    = errors.New("blackjack: not this seat's turn")
+	ErrIllegalAction  = errors.New("blackjack: action not allowed on this hand")
+	ErrInvalidBet     = errors.New("blackjack: invalid bet")
+	ErrInsufficient   = errors.New("blackjack: not enough money in the player's balance")
+	ErrNoBets         = errors.New("blackjack: no bets placed")
+	ErrTableFull      = errors.New("blackjack: no free seat")
+	ErrEmptySeat      = errors.New("blackjack: no player in this seat")
+	ErrDecisionNeeded = errors.New("blackjack: seat has no AI to decide")
+)
+
+// Type EngineOptions is used to configure NewEngine.
+//
+// Seats: number of seats at the table. Defaults to 7. Set with WithSeats function
+//
+// Shoe: shoe to deal from instead of a shuffled shoe built from the rules. Set with WithShoe function
+//
+// DeckOptions: options used to build and shuffle the shoe, such as deck.WithSeed. Set with
+// WithDeckOptions function
+//
+// Seed: seed used to shuffle the shoe, kept in hand histories so the shoe can be dealt
+// again. Ignored when a Shoe is given. Set with WithShoeSeed function
+//
+// Listeners: functions that receive every event. Add with WithListener function
+//
+// SideBets: side bets offered at the table. Set with WithSideBets function
+type EngineOptions struct {
+	Seats       int
+	Shoe        *deck.Shoe
+	DeckOptions []deck.OptionFunc
+	Seed        *int64
+	Listeners   []Listener
+	SideBets    []SideBet
+}
+
+// type EngineOptionFunc acts a wrapper for functional
+// options used for configuration in NewEngine
+type EngineOptionFunc func(*EngineOptions)
+
+// Option that sets the number of seats at the table.
+func WithSeats(n int) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.Seats = n
+	}
+}
+
+// Option used to deal from a prepared shoe, e.g one stacked for a test or a replay.
+func WithShoe(shoe *deck.Shoe) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.Shoe = shoe
+	}
+}
+
+// Option used to pass deck options when building the shoe. The shoe is always shuffled.
+func WithDeckOptions(options ...deck.OptionFunc) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.DeckOptions = append(o.DeckOptions, options...)
+	}
+}
+
+// Option used to shuffle the shoe with a seed, see deck.WithSeed.
+func WithShoeSeed(seed int64) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.Seed = &seed
+	}
+}
+
+// Option that adds a listener receiving every event.
+func WithListener(l Listener) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.Listeners = append(o.Listeners, l)
+	}
+}
+
+// Option that offers side bets at the table, e.g PerfectPairs().
+func WithSideBets(bets ...SideBet) EngineOptionFunc {
+	return func(o *EngineOptions) {
+		o.SideBets = append(o.SideBets, bets...)
+	}
+}
+
+// Type PlayerOptions is used to configure a player seated with AddPlayer.
+//
+// Balance: money the player brings to the table. Bets the balance can not cover are
+// rejected and the player leaves the table once unable to make the minimum bet. When
+// not set the player has unlimited money. Set with WithBalance function
+type PlayerOptions struct {
+	Balance *float64
+}
+
+// type PlayerOptionFunc acts a wrapper for functional
+// options used for configuration in AddPlayer
+type PlayerOptionFunc func(*PlayerOptions)
+
+// Option that sets the money a player brings to the table.
+func WithBalance(balance float64) PlayerOptionFunc {
+	return func(o *PlayerOptions) {
+		o.Balance = &balance
+	}
+}
+
+// Engine runs a blackjack table as a state machine without doing any I/O.
+// Players are driven either by calling PlaceBet, Deal, Insure and Act as the phase
+// requires, or by seating them with an AI and calling PlayRound. Everything that happens
+// is reported to the listeners as an Event.
+type Engine struct {
+	rules    Rules
+	strategy DealerStrategy
+	shoe     *deck.Shoe
+	seed     *int64
+	// customDeck is set when the shoe was built with deck options, which the seed
+	// alone does not rebuild.
+	customDeck bool
+	shuffles   int
+	seats      []*seat
+	dealer     Hand
+	revealed   bool
+	phase      Phase
+	round      int
+	turn       int
+	hand       int
+	listeners  []Listener
+	sideBets   []SideBet
+	// reshuffled holds the shoes a replay deals from after running out of cards.
+	reshuffled []*deck.Shoe
+	// sideBet is wagered on every side bet by AIs not implementing SideBetter, as
+	// Simulate does.
+	sideBet int
+}
+
+type seat struct {
+	name      string
+	ai        AI
+	bet       int
+	hands     []*playerHand
+	insured   bool
+	insurance float64
+	evenMoney bool
+	winnings  float64
+	balance   float64
+	limited   bool
+	// sideBets holds the wager on each side bet offered, dealt the first two cards.
+	sideBets []int
+	dealt    []deck.Card
+}
+
+// playerHand is one of the hands a seat holds, there is more
+// than one after splitting a pair.
+type playerHand struct {
+	hand        Hand
+	bet         int
+	split       bool
+	doubled     bool
+	surrendered bool
+	done        bool
+}
+
+// isNatural reports whether the hand is a blackjack that was dealt, a two card
+// 21 made after splitting does not count.
+func (h *playerHand) isNatural() bool {
+	return !h.split && h.hand.IsBlackjack()
+}
+
+// canAfford reports whether the seat's balance covers its stake in the round plus extra.
+func (s *seat) canAfford(extra float64) bool {
+	if !s.limited {
+		return true
+	}
+	staked := s.insurance + float64(s.sideBetTotal())
+	for _, h := range s.hands {
+		staked += float64(h.bet)
+	}
+	return staked+extra <= s.balance
+}
+
+// sideBetTotal returns the money wagered on side bets.
+func (s *seat) sideBetTotal() int {
+	var total int
+	for _, amount := range s.sideBets {
+		total += amount
+	}
+	return total
+}
+
+// playing reports whether the seat was dealt into the current round.
+func (s *seat) playing() bool {
+	return len(s.hands) > 0
+}
+
+// NewEngine creates an engine for a table with the given rules.
+func NewEngine(rules Rules, options ...EngineOptionFunc) (*Engine, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	o := EngineOptions{Seats: 7}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.Seats < 1 {
+		return nil, fmt.Errorf("blackjack: table needs at least one seat, got %d", o.Seats)
+	}
+	if err := checkSideBets(o.SideBets); err != nil {
+		return nil, err
+	}
+
+	shoe := o.Shoe
+	if shoe != nil {
+		o.Seed = nil
+	} else {
+		var err error
+		deckOptions := append([]deck.OptionFunc{deck.WithShuffle()}, o.DeckOptions...)
+		if o.Seed != nil {
+			deckOptions = append(deckOptions, deck.WithSeed(*o.Seed))
+		}
+		shoe, err = deck.NewShoe(rules.Decks, rules.Penetration, deckOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Engine{
+		rules:      rules,
+		strategy:   rules.DealerStrategy(),
+		shoe:       shoe,
+		seed:       o.Seed,
+		customDeck: o.Shoe == nil && len(o.DeckOptions) > 0,
+		seats:      make([]*seat, o.Seats),
+		listeners:  o.Listeners,
+		sideBets:   o.SideBets,
+	}, nil
+}
+
+// Rules returns the rules of the table.
+func (e *Engine) Rules() Rules {
+	return e.rules
+}
+
+// SideBets returns the side bets offered at the table.
+func (e *Engine) SideBets() []SideBet {
+	return e.sideBets
+}
+
+// Phase returns the phase the engine is waiting in.
+func (e *Engine) Phase() Phase {
+	return e.phase
+}
+
+// AddListener adds a listener receiving every event from now on.
+func (e *Engine) AddListener(l Listener) {
+	e.listeners = append(e.listeners, l)
+}
+
+// AddPlayer seats a player at the first free seat and returns it. The ai is used by
+// PlayRound to make the player's decisions and can be nil when the player is driven
+// with PlaceBet, Insure and Act. A player joining during a round plays from the next round.
+func (e *Engine) AddPlayer(name string, ai AI, options ...PlayerOptionFunc) (int, error) {
+	var o PlayerOptions
+	for _, option := range options {
+		option(&o)
+	}
+	i := slices.Index(e.seats, nil)
+	if i < 0 {
+		return 0, ErrTableFull
+	}
+	s := &seat{name: name, ai: ai}
+	if o.Balance != nil {
+		s.balance, s.limited = *o.Balance, true
+	}
+	e.seats[i] = s
+	if so, ok := ai.(SeatObserver); ok {
+		so.ObserveSeat(e, i)
+	}
+	e.emit(PlayerJoined{Seat: i, Name: name})
+	return i, nil
+}
+
+// RemovePlayer frees a seat. A player can not leave in the middle of a round they were dealt into.
+func (e *Engine) RemovePlayer(i int) error {
+	s, err := e.seat(i)
+	if err != nil {
+		return err
+	}
+	if s.playing() && e.phase != PhaseIdle {
+		return ErrWrongPhase
+	}
+	e.seats[i] = nil
+	e.emit(PlayerLeft{Seat: i, Name: s.name})
+	return nil
+}
+
+// NewRound clears the table and opens a new round for betting, reshuffling the shoe
+// if the cut card was reached.
+func (e *Engine) NewRound() error {
+	if e.phase != PhaseIdle {
+		return ErrWrongPhase
+	}
+
+	e.dealer = Hand{}
+	e.revealed = false
+	for _, s := range e.seats {
+		if s == nil {
+			continue
+		}
+		s.bet = 0
+		s.hands = nil
+		s.insured = false
+		s.insurance = 0
+		s.evenMoney = false
+		s.sideBets = nil
+		s.dealt = nil
+	}
+	if e.shoe.NeedsReshuffle() {
+		e.shuffle()
+	}
+
+	e.round++
+	e.phase = PhaseBetting
+	e.emit(RoundStarted{Round: e.round})
+	return nil
+}
+
+// PlaceBet sets the bet of a seat for the round. Seats without a bet sit the round out.
+// The bet must be within the table limits and covered by the player's balance.
+func (e *Engine) PlaceBet(i, amount int) error {
+	if e.phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+	s, err := e.seat(i)
+	if err != nil {
+		return err
+	}
+	if err := e.rules.checkBet(amount); err != nil {
+		return err
+	}
+	if s.limited && float64(amount+s.sideBetTotal()) > s.balance {
+		return fmt.Errorf("%w: bet %d, balance %v", ErrInsufficient, amount, s.balance)
+	}
+
+	s.bet = amount
+	e.emit(BetPlaced{Seat: i, Amount: amount})
+	return nil
+}
+
+// PlaceSideBet wagers an amount on one of the side bets offered, 0 taking the wager
+// back. Side bets are placed after the main bet and settled with the round.
+func (e *Engine) PlaceSideBet(i int, name string, amount int) error {
+	if e.phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+	s, err := e.seat(i)
+	if err != nil {
+		return err
+	}
+	b := slices.IndexFunc(e.sideBets, func(sb SideBet) bool { return sb.Name == name })
+	switch {
+	case b < 0:
+		return fmt.Errorf("%w: %q", ErrUnknownSideBet, name)
+	case amount < 0:
+		return fmt.Errorf("%w: %d", ErrInvalidBet, amount)
+	case s.bet == 0:
+		return fmt.Errorf("%w: side bets need a main bet", ErrNoBets)
+	}
+	if s.sideBets == nil {
+		s.sideBets = make([]int, len(e.sideBets))
+	}
+	if s.limited && float64(s.bet+s.sideBetTotal()-s.sideBets[b]+amount) > s.balance {
+		return fmt.Errorf("%w: side bet %d, balance %v", ErrInsufficient, amount, s.balance)
+	}
+
+	s.sideBets[b] = amount
+	e.emit(SideBetPlaced{Seat: i, Name: name, Amount: amount})
+	return nil
+}
+
+// Deal deals two cards to every seat with a bet and to the dealer, whose
+// second card is dealt face down.
+func (e *Engine) Deal() error {
+	if e.phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+
+	var playing []int
+	for i, s := range e.seats {
+		if s != nil && s.bet > 0 {
+			s.hands = []*playerHand{{bet: s.bet}}
+			playing = append(playing, i)
+		}
+	}
+	if len(playing) == 0 {
+		return ErrNoBets
+	}
+
+	for round := 0; round < 2; round++ {
+		e.dealDealer(round == 1)
+		for _, i := range playing {
+			e.dealTo(i, 0)
+		}
+	}
+
+	for _, i := range playing {
+		e.seats[i].dealt = slices.Clone(e.seats[i].hands[0].hand.Hand)
+		if h := e.seats[i].hands[0]; h.isNatural() {
+			h.done = true
+			e.emit(PlayerBlackjack{Seat: i})
+		}
+	}
+
+	if e.UpCard().Type == deck.ACE {
+		e.phase = PhaseInsurance
+		return nil
+	}
+	e.afterInsurance()
+	return nil
+}
+
+// Insure records whether a seat takes insurance, or even money when holding a
+// blackjack. Insurance costs half the bet and pays 2:1 if the dealer has a blackjack,
+// taking it fails with ErrInsufficient when the balance can not cover it.
+func (e *Engine) Insure(i int, take bool) error {
+	if e.phase != PhaseInsurance {
+		return ErrWrongPhase
+	}
+	s, err := e.seat(i)
+	if err != nil {
+		return err
+	}
+	if !s.playing() || s.insured {
+		return ErrNotYourTurn
+	}
+
+	if take && !e.canInsure(s) {
+		return ErrInsufficient
+	}
+
+	s.insured = true
+	if take {
+		if s.hands[0].isNatural() {
+			s.evenMoney = true
+		} else {
+			s.insurance = float64(s.bet) / 2
+		}
+	}
+	e.emit(InsuranceDecided{Seat: i, Taken: take, EvenMoney: s.evenMoney, Amount: s.insurance})
+
+	if _, pending := e.PendingInsurance(); !pending {
+		e.afterInsurance()
+	}
+	return nil
+}
+
+// PendingInsurance returns the first seat that still has to decide on insurance.
+func (e *Engine) PendingInsurance() (int, bool) {
+	if e.phase != PhaseInsurance {
+		return 0, false
+	}
+	for i, s := range e.seats {
+		if s != nil && s.playing() && !s.insured {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Turn returns the seat and hand whose turn it is.
+func (e *Engine) Turn() (seat, hand int, ok bool) {
+	if e.phase != PhasePlayerTurn {
+		return 0, 0, false
+	}
+	return e.turn, e.hand, true
+}
+
+// LegalActions returns the actions allowed on the hand whose turn it is.
+func (e *Engine) LegalActions() []Action {
+	if e.phase != PhasePlayerTurn {
+		return nil
+	}
+	s := e.seats[e.turn]
+	h := s.hands[e.hand]
+	if h.split && h.hand.Hand[0].Type == deck.ACE && !e.rules.HitSplitAces {
+		// Split aces left open can only be split again.
+		return []Action{Stand, Split}
+	}
+
+	actions := []Action{Hit, Stand}
+	afford := s.canAfford(float64(h.bet))
+	if len(h.hand.Hand) == 2 && (!h.split || e.rules.DoubleAfterSplit) && e.rules.canDouble(h.hand.Value()) && afford {
+		actions = append(actions, DoubleDown)
+	}
+	if e.canSplit(s, h) {
+		actions = append(actions, Split)
+	}
+	if e.rules.Surrender && len(s.hands) == 1 && !h.split && len(h.hand.Hand) == 2 {
+		actions = append(actions, Surrender)
+	}
+	return actions
+}
+
+// Act plays an action on the hand whose turn it is.
+func (e *Engine) Act(i int, action Action) error {
+	if e.phase != PhasePlayerTurn {
+		return ErrWrongPhase
+	}
+	if i != e.turn {
+		return ErrNotYourTurn
+	}
+	if !slices.Contains(e.LegalActions(), action) {
+		return fmt.Errorf("%w: %s", ErrIllegalAction, action)
+	}
+
+	s := e.seats[i]
+	h := s.hands[e.hand]
+	e.emit(PlayerActed{Seat: i, Hand: e.hand, Action: action})
+
+	switch action {
+	case Hit:
+		e.dealTo(i, e.hand)
+	case Stand:
+		h.done = true
+	case DoubleDown:
+		h.bet *= 2
+		h.doubled = true
+		e.dealTo(i, e.hand)
+		h.done = true
+	case Split:
+		second := h.hand.Hand[1]
+		h.hand = Hand{Hand: []deck.Card{h.hand.Hand[0]}}
+		h.split = true
+		s.hands = append(s.hands, &playerHand{
+			hand:  Hand{Hand: []deck.Card{second}},
+			bet:   h.bet,
+			split: true,
+		})
+		e.dealTo(i, e.hand)
+		e.checkSplitHand(s, h)
+	case Surrender:
+		h.surrendered = true
+		h.done = true
+	}
+
+	e.advance()
+	return nil
+}
+
+// PlayRound plays a full round, asking the AI of every seat for its decisions.
+// Seats without an AI sit the round out, unless they already placed a bet in which
+// case ErrDecisionNeeded is returned when they have to act.
+func (e *Engine) PlayRound() error {
+	if e.phase == PhaseIdle {
+		if err := e.NewRound(); err != nil {
+			return err
+		}
+	}
+
+	if e.phase == PhaseBetting {
+		for i, s := range e.seats {
+			if s == nil || s.ai == nil {
+				continue
+			}
+			if bet := e.adjustBet(i, "", s.ai.DecideBet(), s.bet); bet > 0 {
+				if err := e.PlaceBet(i, bet); err != nil {
+					return err
+				}
+				if err := e.placeSideBets(i, s.ai); err != nil {
+					return err
+				}
+			}
+		}
+		if err := e.Deal(); err != nil {
+			return err
+		}
+	}
+
+	for e.phase == PhaseInsurance {
+		i, _ := e.PendingInsurance()
+		s := e.seats[i]
+		if s.ai == nil {
+			return ErrDecisionNeeded
+		}
+		// A player who can not afford insurance is not offered it.
+		take := e.canInsure(s) && s.ai.DecideInsurance(s.hands[0].hand)
+		if err := e.Insure(i, take); err != nil {
+			return err
+		}
+	}
+
+	for e.phase == PhasePlayerTurn {
+		s := e.seats[e.turn]
+		if s.ai == nil {
+			return ErrDecisionNeeded
+		}
+		action := ChooseAction(s.ai, s.hands[e.hand].hand, e.UpCard(), e.LegalActions())
+		if err := e.Act(e.turn, action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// placeSideBets asks an AI implementing SideBetter for its side bets, other AIs
+// wager the engine's default side bet.
+func (e *Engine) placeSideBets(i int, ai AI) error {
+	decide := func(SideBet) int { return e.sideBet }
+	if sb, ok := ai.(SideBetter); ok {
+		decide = sb.DecideSideBet
+	}
+	s := e.seats[i]
+	for b, bet := range e.sideBets {
+		var placed int
+		if s.sideBets != nil {
+			placed = s.sideBets[b]
+		}
+		if amount := e.adjustBet(i, bet.Name, decide(bet), placed); amount > 0 {
+			if err := e.PlaceSideBet(i, bet.Name, amount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adjustBet fits a bet an AI asked for to the table, like a dealer would. A main bet
+// below the table minimum is sat out and one above the maximum is cut down to it.
+// Any bet is cut down to the money the player has left besides the other bets, less
+// the amount the bet replaces, and sat out when nothing is left. BetAdjusted is
+// emitted when the bet changes.
+func (e *Engine) adjustBet(i int, sideBet string, amount, replaced int) int {
+	if amount <= 0 {
+		return 0
+	}
+	s := e.seats[i]
+	bet, minBet, reason := amount, 1, ""
+	if sideBet == "" {
+		minBet = max(e.rules.MinBet, 1)
+		switch {
+		case amount < minBet:
+			bet, reason = 0, "below the table minimum"
+		case e.rules.MaxBet > 0 && amount > e.rules.MaxBet:
+			bet, reason = e.rules.MaxBet, "above the table maximum"
+		}
+	}
+	if left := int(math.Floor(s.balance)) - s.bet - s.sideBetTotal() + replaced; s.limited && bet > left {
+		bet, reason = left, "not enough money"
+		if bet < minBet {
+			bet = 0
+		}
+	}
+	if bet != amount {
+		e.emit(BetAdjusted{Seat: i, SideBet: sideBet, Requested: amount, Amount: bet, Reason: reason})
+	}
+	return bet
+}
+
+// UpCard returns the dealer's face up card.
+func (e *Engine) UpCard() deck.Card {
+	if len(e.dealer.Hand) == 0 {
+		return deck.Card{}
+	}
+	return e.dealer.Hand[0]
+}
+
+// canInsure reports whether the seat can pay for insurance, even money is always possible.
+func (e *Engine) canInsure(s *seat) bool {
+	return s.hands[0].isNatural() || s.canAfford(float64(s.bet)/2)
+}
+
+func (e *Engine) seat(i int) (*seat, error) {
+	if i < 0 || i >= len(e.seats) || e.seats[i] == nil {
+		return nil, fmt.Errorf("%w: %d", ErrEmptySeat, i)
+	}
+	return e.seats[i], nil
+}
+
+func (e *Engine) emit(ev Event) {
+	e.observe(ev)
+	for _, l := range e.listeners {
+		l(ev)
+	}
+}
+
+// observe shows the cards revealed by an event to the AIs implementing CardObserver.
+func (e *Engine) observe(ev Event) {
+	var card deck.Card
+	switch ev := ev.(type) {
+	case CardDealt:
+		if ev.FaceDown {
+			return
+		}
+		card = ev.Card
+	case HoleCardRevealed:
+		card = ev.Card
+	case ShoeShuffled:
+	default:
+		return
+	}
+
+	for _, s := range e.seats {
+		if s == nil {
+			continue
+		}
+		o, ok := s.ai.(CardObserver)
+		if !ok {
+			continue
+		}
+		if ev, ok := ev.(ShoeShuffled); ok {
+			o.ObserveShuffle(ev.Decks)
+		} else {
+			o.ObserveCard(card)
+		}
+	}
+}
+
+func (e *Engine) shuffle() {
+	if len(e.reshuffled) > 0 {
+		e.shoe, e.reshuffled = e.reshuffled[0], e.reshuffled[1:]
+	} else {
+		e.shoe.Shuffle()
+	}
+	e.shuffles++
+	e.emit(ShoeShuffled{Decks: e.shoe.Decks()})
+}
+
+func (e *Engine) draw() deck.Card {
+	c, err := e.shoe.Draw()
+	if errors.Is(err, deck.ErrEmpty) {
+		// Only happens with very deep penetration, reshuffle and keep dealing.
+		e.shuffle()
+		c, _ = e.shoe.Draw()
+	}
+	return c
+}
+
+func (e *Engine) dealDealer(faceDown bool) {
+	c := e.draw()
+	e.dealer.addCard(c)
+	if faceDown {
+		e.emit(CardDealt{Seat: DealerSeat, FaceDown: true})
+		return
+	}
+	e.emit(CardDealt{Seat: DealerSeat, Card: c})
+}
+
+func (e *Engine) dealTo(i, hand int) {
+	c := e.draw()
+	h := e.seats[i].hands[hand]
+	h.hand.addCard(c)
+	e.emit(CardDealt{Seat: i, Hand: hand, Card: c})
+
+	switch v := h.hand.Value(); {
+	case v > 21:
+		h.done = true
+		e.emit(PlayerBusts{Seat: i, Hand: hand, Value: v})
+	case v == 21:
+		h.done = true
+	}
+}
+
+// checkSplitHand ends a hand made by splitting aces once it has its second card,
+// unless it is a pair of aces again that can be split.
+func (e *Engine) checkSplitHand(s *seat, h *playerHand) {
+	if h.hand.Hand[0].Type == deck.ACE && !e.rules.HitSplitAces {
+		h.done = !e.canSplit(s, h)
+	}
+}
+
+// canSplit reports whether the hand can be split.
+func (e *Engine) canSplit(s *seat, h *playerHand) bool {
+	return h.hand.IsPair() && len(s.hands) <= e.rules.MaxSplits && s.canAfford(float64(h.bet)) &&
+		(e.rules.ResplitAces || !(h.split && h.hand.Hand[0].Type == deck.ACE))
+}
+
+// dealerPeeks reports whether the dealer checks the hole card for a blackjack
+// before the players act, which happens when showing an ace or a ten.
+func (e *Engine) dealerPeeks() bool {
+	return cardValue(e.UpCard()) == 10 || e.UpCard().Type == deck.ACE
+}
+
+func (e *Engine) reveal() {
+	if e.revealed {
+		return
+	}
+	e.revealed = true
+	e.emit(HoleCardRevealed{Card: e.dealer.Hand[1]})
+}
+
+// afterInsurance checks the hole card for a dealer blackjack, then starts the players' turns.
+func (e *Engine) afterInsurance() {
+	if e.dealerPeeks() && e.dealer.IsBlackjack() {
+		e.reveal()
+		e.emit(DealerBlackjack{})
+		e.settle()
+		return
+	}
+
+	e.phase = PhasePlayerTurn
+	e.turn, e.hand = 0, 0
+	e.advance()
+}
+
+// advance moves the turn to the next hand that needs an action, dealing the
+// second card to hands made by splitting. Once every hand is done the dealer plays
+// and the round is settled.
+func (e *Engine) advance() {
+	for ; e.turn < len(e.seats); e.turn, e.hand = e.turn+1, 0 {
+		s := e.seats[e.turn]
+		if s == nil || !s.playing() {
+			continue
+		}
+		for ; e.hand < len(s.hands); e.hand++ {
+			h := s.hands[e.hand]
+			if len(h.hand.Hand) == 1 {
+				e.dealTo(e.turn, e.hand)
+				e.checkSplitHand(s, h)
+			}
+			if !h.done {
+				return
+			}
+		}
+	}
+
+	e.playDealer()
+	e.settle()
+}
+
+func (e *Engine) playDealer() {
+	e.reveal()
+
+	live := false
+	for _, s := range e.seats {
+		if s == nil || s.evenMoney {
+			continue
+		}
+		for _, h := range s.hands {
+			live = live || (h.hand.Value() <= 21 && !h.surrendered && !h.isNatural())
+		}
+	}
+	if !live {
+		return
+	}
+
+	for e.strategy.DecideHit(e.dealer) {
+		e.dealDealer(false)
+	}
+	if v := e.dealer.Value(); v > 21 {
+		e.emit(DealerBusts{Value: v})
+	} else {
+		e.emit(DealerStands{Value: v})
+	}
+}
+
+func (e *Engine) settle() {
+	dealerBlackjack := e.dealer.IsBlackjack()
+	settled := RoundSettled{Round: e.round, Dealer: append([]deck.Card(nil), e.dealer.Hand...)}
+
+	for i, s := range e.seats {
+		if s == nil || !s.playing() {
+			continue
+		}
+		result := SeatResult{Seat: i, Name: s.name}
+
+		if s.insurance > 0 {
+			result.Insurance = -s.insurance
+			if dealerBlackjack {
+				result.Insurance = 2 * s.insurance
+			}
+		}
+		result.Net = result.Insurance
+
+		for _, h := range s.hands {
+			outcome, amount := e.settleHand(s, h)
+			result.Hands = append(result.Hands, HandResult{
+				Cards:   append([]deck.Card(nil), h.hand.Hand...),
+				Bet:     h.bet,
+				Outcome: outcome,
+				Amount:  amount,
+			})
+			result.Net += amount
+		}
+		for b, amount := range s.sideBets {
+			if amount == 0 {
+				continue
+			}
+			r := SideBetResult{Name: e.sideBets[b].Name, Bet: amount, Amount: -float64(amount)}
+			if outcome, pays, ok := e.sideBets[b].Payout(s.dealt, e.dealer.Hand[:2]); ok {
+				r.Outcome, r.Amount = outcome, float64(amount*pays)
+			}
+			result.SideBets = append(result.SideBets, r)
+			result.Net += r.Amount
+		}
+
+		s.winnings += result.Net
+		s.balance += result.Net
+		settled.Results = append(settled.Results, result)
+	}
+
+	e.phase = PhaseIdle
+	e.emit(settled)
+
+	for i, s := range e.seats {
+		if s != nil && s.limited && s.balance < float64(max(e.rules.MinBet, 1)) {
+			e.seats[i] = nil
+			e.emit(PlayerLeft{Seat: i, Name: s.name, Broke: true})
+		}
+	}
+}
+
+// settleHand returns the outcome of a hand and the amount won (positive) or lost (negative).
+func (e *Engine) settleHand(s *seat, h *playerHand) (Outcome, float64) {
+	bet := float64(h.bet)
+
+	switch {
+	case s.evenMoney:
+		return EvenMoney, bet
+	case h.surrendered:
+		return Surrendered, -bet / 2
+	case h.isNatural():
+		if e.dealer.IsBlackjack() {
+			return Push, 0
+		}
+		return Blackjack, e.rules.BlackjackPayout.Of(bet)
+	case e.dealer.IsBlackjack():
+		return Lose, -bet
+	}
+
+	switch getWinner(h.hand, e.dealer) {
+	case 1:
+		return Win, bet
+	case -1:
+		return Lose, -bet
+	}
+	return Push, 0
+}
+
+// ChooseAction asks an AI for one of the legal actions, with ChooseAction when it is
+// an ActionChooser and with its decision hooks otherwise. An illegal choice stands.
+func ChooseAction(ai AI, hand Hand, up deck.Card, legal []Action) Action {
+	if c, ok := ai.(ActionChooser); ok {
+		if a := c.ChooseAction(hand, up, legal); slices.Contains(legal, a) {
+			return a
+		}
+		return Stand
+	}
+
+	switch {
+	case slices.Contains(legal, Surrender) && ai.DecideSurrender(hand, up):
+		return Surrender
+	case slices.Contains(legal, Split) && ai.DecideSplit(hand, up):
+		return Split
+	case slices.Contains(legal, DoubleDown) && ai.DoubleDown(hand, up):
+		return DoubleDown
+	case ai.DecideHit(hand, up):
+		return Hit
+	}
+	return Stand
+}
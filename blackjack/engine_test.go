@@ -0,0 +1,266 @@
+package blackjack
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func stackedEngine(t *testing.T, cards string, options ...EngineOptionFunc) *Engine {
+	t.Helper()
+	stacked, err := deck.ParseDeck(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe, err := deck.NewShoeFromCards(stacked, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEngine(DefaultRules(), append(options, WithShoe(shoe))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestEngineDrivenByActions(t *testing.T) {
+	var events []Event
+	e := stackedEngine(t, "Ts 5s 7c 6h 9d Kd 2c", WithListener(func(ev Event) {
+		events = append(events, ev)
+	}))
+
+	alice, _ := e.AddPlayer("Alice", nil)
+	bob, _ := e.AddPlayer("Bob", nil)
+
+	if err := e.PlaceBet(alice, 10); !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase before the round starts, got %v", err)
+	}
+	e.NewRound()
+	if err := e.Deal(); !errors.Is(err, ErrNoBets) {
+		t.Fatalf("expected ErrNoBets, got %v", err)
+	}
+	if err := e.PlaceBet(alice, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Deal(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob did not bet and sits the round out, Alice holds 5-6 against a ten.
+	if seat, hand, ok := e.Turn(); !ok || seat != alice || hand != 0 {
+		t.Fatalf("expected Alice's turn, got seat %d hand %d", seat, hand)
+	}
+	if err := e.Act(bob, Hit); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected ErrNotYourTurn, got %v", err)
+	}
+	if err := e.Act(alice, Split); !errors.Is(err, ErrIllegalAction) {
+		t.Fatalf("expected ErrIllegalAction, got %v", err)
+	}
+	if err := e.Act(alice, DoubleDown); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice doubles 11 into 20 with the nine and beats the dealer standing on 17.
+	st := e.State()
+	if st.Phase != PhaseIdle || st.DealerHidden {
+		t.Fatalf("expected settled round with dealer revealed, got %+v", st)
+	}
+	if s, _ := st.Seat(alice); s.Winnings != 20 {
+		t.Fatalf("expected Alice to win 20, got %v", s.Winnings)
+	}
+
+	var settled *RoundSettled
+	faceDown := 0
+	for _, ev := range events {
+		switch ev := ev.(type) {
+		case CardDealt:
+			if ev.FaceDown {
+				faceDown++
+				if ev.Card != (deck.Card{}) {
+					t.Fatal("expected hole card to be hidden")
+				}
+			}
+		case RoundSettled:
+			settled = &ev
+		}
+	}
+	if faceDown != 1 {
+		t.Fatalf("expected one face down card, got %d", faceDown)
+	}
+	if settled == nil || len(settled.Results) != 1 || settled.Results[0].Hands[0].Outcome != Win {
+		t.Fatalf("expected Alice to win the round, got %+v", settled)
+	}
+}
+
+func TestEngineNeedsDecision(t *testing.T) {
+	e := stackedEngine(t, "Ts 5s 7c 6h")
+	seat, _ := e.AddPlayer("Alice", nil)
+
+	e.NewRound()
+	e.PlaceBet(seat, 10)
+	if err := e.PlayRound(); !errors.Is(err, ErrDecisionNeeded) {
+		t.Fatalf("expected ErrDecisionNeeded, got %v", err)
+	}
+	if err := e.Act(seat, Stand); err != nil {
+		t.Fatal(err)
+	}
+	if e.Phase() != PhaseIdle {
+		t.Fatalf("expected round to be over, got %s", e.Phase())
+	}
+}
+
+func TestEngineResplitAces(t *testing.T) {
+	stacked, _ := deck.ParseDeck("9s As 7c Ah Ad Kd 5h")
+	shoe, _ := deck.NewShoeFromCards(stacked, 1, 1)
+	rules := DefaultRules()
+	rules.ResplitAces = true
+	e, err := NewEngine(rules, WithShoe(shoe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seat, _ := e.AddPlayer("Alice", nil)
+	e.NewRound()
+	e.PlaceBet(seat, 10)
+	e.Deal()
+	if err := e.Act(seat, Split); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first split ace draws another ace and may only be split again.
+	if _, hand, _ := e.Turn(); hand != 0 || !slices.Equal(e.LegalActions(), []Action{Stand, Split}) {
+		t.Fatalf("expected to split the aces again, got hand %d and %v", hand, e.LegalActions())
+	}
+	if err := e.Act(seat, Hit); !errors.Is(err, ErrIllegalAction) {
+		t.Fatalf("expected hitting split aces to be illegal, got %v", err)
+	}
+	if err := e.Act(seat, Stand); err != nil {
+		t.Fatal(err)
+	}
+	if e.Phase() != PhaseIdle {
+		t.Fatalf("expected the second ace to end the round with the king, got %s", e.Phase())
+	}
+}
+
+func TestEngineSeats(t *testing.T) {
+	e, err := NewEngine(DefaultRules(), WithSeats(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.AddPlayer("Alice", nil)
+	bob, _ := e.AddPlayer("Bob", nil)
+	if _, err := e.AddPlayer("Carol", nil); !errors.Is(err, ErrTableFull) {
+		t.Fatalf("expected ErrTableFull, got %v", err)
+	}
+	if err := e.RemovePlayer(bob); err != nil {
+		t.Fatal(err)
+	}
+	if seat, err := e.AddPlayer("Carol", nil); err != nil || seat != bob {
+		t.Fatalf("expected Carol to take seat %d, got %d (%v)", bob, seat, err)
+	}
+}
+
+func TestEngineBankroll(t *testing.T) {
+	rules := DefaultRules()
+	rules.MinBet, rules.MaxBet = 10, 100
+	stacked, _ := deck.ParseDeck("Ts 5s 7c 6h Kd 9s Ts 9c 7d")
+	shoe, _ := deck.NewShoeFromCards(stacked, 1, 1)
+	var left []PlayerLeft
+	e, err := NewEngine(rules, WithShoe(shoe), WithListener(func(ev Event) {
+		if ev, ok := ev.(PlayerLeft); ok {
+			left = append(left, ev)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, _ := e.AddPlayer("Alice", nil, WithBalance(15))
+
+	e.NewRound()
+	for _, bet := range []int{5, 200} {
+		if err := e.PlaceBet(alice, bet); !errors.Is(err, ErrInvalidBet) {
+			t.Fatalf("expected ErrInvalidBet for a bet of %d, got %v", bet, err)
+		}
+	}
+	if err := e.PlaceBet(alice, 20); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("expected ErrInsufficient, got %v", err)
+	}
+	e.PlaceBet(alice, 10)
+	e.Deal()
+
+	// 11 against a ten, but the balance does not cover doubling.
+	if slices.Contains(e.LegalActions(), DoubleDown) {
+		t.Fatalf("expected doubling not to be allowed, got %v", e.LegalActions())
+	}
+	e.Act(alice, Hit)
+	if s, _ := e.State().Seat(alice); s.Balance == nil || *s.Balance != 25 {
+		t.Fatalf("expected a balance of 25 after winning, got %+v", s)
+	}
+
+	// Losing everything on 17 against 18 leaves Alice broke.
+	e.NewRound()
+	e.PlaceBet(alice, 25)
+	e.Deal()
+	e.Act(alice, Stand)
+	if len(left) != 1 || !left[0].Broke || len(e.State().Seats) != 0 {
+		t.Fatalf("expected Alice to leave broke, got %+v", left)
+	}
+}
+
+func TestPlayRoundAdjustsBets(t *testing.T) {
+	rules := DefaultRules()
+	rules.MinBet, rules.MaxBet = 10, 100
+	var adjusted []BetAdjusted
+	e, err := NewEngine(rules, WithShoeSeed(1), WithSideBets(PerfectPairs()), WithListener(func(ev Event) {
+		if ev, ok := ev.(BetAdjusted); ok {
+			adjusted = append(adjusted, ev)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.AddPlayer("Low", scriptedAI{hitBelow: 17, bet: 5})
+	e.AddPlayer("High", scriptedAI{hitBelow: 17, bet: 500})
+	e.AddPlayer("Short", sideBettingAI{scriptedAI{hitBelow: 17, bet: 50}}, WithBalance(55))
+
+	// The round is played for the others when a bet is not taken as asked.
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+	want := []BetAdjusted{
+		{Seat: 0, Requested: 5, Amount: 0, Reason: "below the table minimum"},
+		{Seat: 1, Requested: 500, Amount: 100, Reason: "above the table maximum"},
+		{Seat: 2, SideBet: PerfectPairsName, Requested: len(PerfectPairsName), Amount: 5, Reason: "not enough money"},
+	}
+	if !reflect.DeepEqual(adjusted, want) {
+		t.Fatalf("expected adjusted bets %+v, got %+v", want, adjusted)
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	var events []Event
+	e := stackedEngine(t, "Ts 5s 7c 6h 9d", WithListener(func(ev Event) {
+		events = append(events, ev)
+	}))
+	e.AddPlayer("Alice", scriptedAI{double: true, bet: 10})
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ev := range events {
+		data, err := MarshalEvent(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := UnmarshalEvent(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, ev) {
+			t.Fatalf("expected %#v after decoding %s, got %#v", ev, data, decoded)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// DealerSeat is the seat used in events for cards dealt to the dealer.
+const DealerSeat = -1
+
+// Event is emitted by the Engine every time something happens at the table.
+// Use a type switch to tell the events apart.
+type Event interface {
+	event()
+}
+
+// Listener receives every event emitted by an Engine.
+type Listener func(Event)
+
+// RoundStarted is emitted when a new round opens for betting.
+type RoundStarted struct {
+	Round int `json:"round"`
+}
+
+// ShoeShuffled is emitted when the shoe is reshuffled.
+type ShoeShuffled struct {
+	Decks int `json:"decks"`
+}
+
+// PlayerJoined is emitted when a player sits down at a seat.
+type PlayerJoined struct {
+	Seat int    `json:"seat"`
+	Name string `json:"name"`
+}
+
+// PlayerLeft is emitted when a player leaves the table. Broke is set when the
+// player had to leave after running out of money.
+type PlayerLeft struct {
+	Seat  int    `json:"seat"`
+	Name  string `json:"name"`
+	Broke bool   `json:"broke"`
+}
+
+// BetPlaced is emitted when a player places a bet for the round.
+type BetPlaced struct {
+	Seat   int `json:"seat"`
+	Amount int `json:"amount"`
+}
+
+// BetAdjusted is emitted by PlayRound when the table does not take a bet as an AI
+// asked for it, before the bet is placed. SideBet names the side bet, empty for the
+// main bet. An Amount of 0 means the player sits the round, or the side bet, out.
+type BetAdjusted struct {
+	Seat      int    `json:"seat"`
+	SideBet   string `json:"sideBet,omitempty"`
+	Requested int    `json:"requested"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason"`
+}
+
+// SideBetPlaced is emitted when a player wagers on a side bet, an Amount of 0 takes the wager back.
+type SideBetPlaced struct {
+	Seat   int    `json:"seat"`
+	Name   string `json:"name"`
+	Amount int    `json:"amount"`
+}
+
+// CardDealt is emitted for every card dealt. The dealer's hole card is dealt
+// face down, its Card is left empty until HoleCardRevealed.
+type CardDealt struct {
+	Seat     int       `json:"seat"`
+	Hand     int       `json:"hand"`
+	Card     deck.Card `json:"card"`
+	FaceDown bool      `json:"faceDown"`
+}
+
+// HoleCardRevealed is emitted when the dealer flips the hole card.
+type HoleCardRevealed struct {
+	Card deck.Card `json:"card"`
+}
+
+// InsuranceDecided is emitted when a player accepts or declines insurance, or
+// even money when holding a blackjack.
+type InsuranceDecided struct {
+	Seat      int     `json:"seat"`
+	Taken     bool    `json:"taken"`
+	EvenMoney bool    `json:"evenMoney"`
+	Amount    float64 `json:"amount"`
+}
+
+// PlayerActed is emitted for every action a player takes on a hand.
+type PlayerActed struct {
+	Seat   int    `json:"seat"`
+	Hand   int    `json:"hand"`
+	Action Action `json:"action"`
+}
+
+// PlayerBlackjack is emitted when a player is dealt a natural blackjack.
+type PlayerBlackjack struct {
+	Seat int `json:"seat"`
+}
+
+// PlayerBusts is emitted when a player's hand goes over 21.
+type PlayerBusts struct {
+	Seat  int `json:"seat"`
+	Hand  int `json:"hand"`
+	Value int `json:"value"`
+}
+
+// DealerBlackjack is emitted when the dealer checks the hole card and has a blackjack.
+type DealerBlackjack struct{}
+
+// DealerBusts is emitted when the dealer's hand goes over 21.
+type DealerBusts struct {
+	Value int `json:"value"`
+}
+
+// DealerStands is emitted when the dealer is done drawing.
+type DealerStands struct {
+	Value int `json:"value"`
+}
+
+// RoundSettled is emitted once every bet of the round is paid or collected.
+type RoundSettled struct {
+	Round   int          `json:"round"`
+	Dealer  []deck.Card  `json:"dealer"`
+	Results []SeatResult `json:"results"`
+}
+
+// Outcome is the result of a single hand.
+type Outcome uint8
+
+const (
+	Lose Outcome = iota
+	Push
+	Win
+	Blackjack
+	Surrendered
+	EvenMoney
+)
+
+var outcomeNames = [...]string{"lose", "push", "win", "blackjack", "surrender", "even money"}
+
+func (o Outcome) String() string {
+	if int(o) < len(outcomeNames) {
+		return outcomeNames[o]
+	}
+	return "unknown"
+}
+
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Outcome) UnmarshalText(text []byte) error {
+	i := slices.Index(outcomeNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("blackjack: unknown outcome %q", text)
+	}
+	*o = Outcome(i)
+	return nil
+}
+
+// HandResult is the settlement of one hand. Amount is the money won (positive) or lost (negative).
+type HandResult struct {
+	Cards   []deck.Card `json:"cards"`
+	Bet     int         `json:"bet"`
+	Outcome Outcome     `json:"outcome"`
+	Amount  float64     `json:"amount"`
+}
+
+// SideBetResult is the settlement of a side bet. Outcome is empty when the bet lost.
+type SideBetResult struct {
+	Name    string  `json:"name"`
+	Bet     int     `json:"bet"`
+	Outcome string  `json:"outcome,omitempty"`
+	Amount  float64 `json:"amount"`
+}
+
+// SeatResult is the settlement of every hand a seat played in a round.
+type SeatResult struct {
+	Seat      int             `json:"seat"`
+	Name      string          `json:"name"`
+	Hands     []HandResult    `json:"hands"`
+	SideBets  []SideBetResult `json:"sideBets,omitempty"`
+	Insurance float64         `json:"insurance"`
+	Net       float64         `json:"net"`
+}
+
+func (RoundStarted) event()     {}
+func (ShoeShuffled) event()     {}
+func (PlayerJoined) event()     {}
+func (PlayerLeft) event()       {}
+func (BetPlaced) event()        {}
+func (BetAdjusted) event()      {}
+func (SideBetPlaced) event()    {}
+func (CardDealt) event()        {}
+func (HoleCardRevealed) event() {}
+func (InsuranceDecided) event() {}
+func (PlayerActed) event()      {}
+func (PlayerBlackjack) event()  {}
+func (PlayerBusts) event()      {}
+func (DealerBlackjack) event()  {}
+func (DealerBusts) event()      {}
+func (DealerStands) event()     {}
+func (RoundSettled) event()     {}
+
+// MarshalJSON leaves the card out while it is face down.
+func (e CardDealt) MarshalJSON() ([]byte, error) {
+	type cardDealt CardDealt
+	v := struct {
+		cardDealt
+		Card *deck.Card `json:"card,omitempty"`
+	}{cardDealt: cardDealt(e)}
+	if !e.FaceDown {
+		v.Card = &e.Card
+	}
+	return json.Marshal(v)
+}
+
+// eventTypes maps the type names used by MarshalEvent to the event types.
+var eventTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, ev := range []Event{
+		RoundStarted{}, ShoeShuffled{}, PlayerJoined{}, PlayerLeft{}, BetPlaced{}, BetAdjusted{}, SideBetPlaced{},
+		CardDealt{}, HoleCardRevealed{}, InsuranceDecided{}, PlayerActed{}, PlayerBlackjack{},
+		PlayerBusts{}, DealerBlackjack{}, DealerBusts{}, DealerStands{}, RoundSettled{},
+	} {
+		eventTypes[EventType(ev)] = reflect.TypeOf(ev)
+	}
+}
+
+// EventType returns the name of the type of an event, e.g "cardDealt".
+func EventType(ev Event) string {
+	name := reflect.TypeOf(ev).Name()
+	return string(name[0]+'a'-'A') + name[1:]
+}
+
+type eventJSON struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// MarshalEvent encodes an event as a JSON object holding its type and fields,
+// e.g {"type":"betPlaced","event":{"seat":0,"amount":10}}.
+func MarshalEvent(ev Event) ([]byte, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(eventJSON{Type: EventType(ev), Event: data})
+}
+
+// UnmarshalEvent decodes an event encoded with MarshalEvent.
+func UnmarshalEvent(data []byte) (Event, error) {
+	var j eventJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	t, ok := eventTypes[j.Type]
+	if !ok {
+		return nil, fmt.Errorf("blackjack: unknown event type %q", j.Type)
+	}
+	v := reflect.New(t)
+	if len(j.Event) > 0 {
+		if err := json.Unmarshal(j.Event, v.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return v.Elem().Interface().(Event), nil
+}
@@ -0,0 +1,318 @@
+package blackjack
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// ErrReplayMismatch is returned by Replay when the replayed round differs from the history.
+var ErrReplayMismatch = errors.New("blackjack: replay does not match the history")
+
+// RoundHistory is the record of one round. Events holds everything that happened from
+// RoundStarted to RoundSettled, so it includes every decision as BetPlaced,
+// InsuranceDecided and PlayerActed, the dealer's draws and the payouts.
+//
+// Shoe holds the cards left in the shoe when the round started, in the order they are
+// dealt. When the engine was shuffled with a seed, Seed, Shuffles (the reshuffles since
+// the engine was created) and ShoeDealt (the cards dealt before the round) rebuild the
+// same shoe, see NewShoe, unless CustomDeck tells the shoe was built with deck options. When the shoe runs out of cards in the middle of the
+// round, Reshuffled holds every card of the shoe in the order it was shuffled to.
+//
+// CustomSideBets names the side bets offered with an Evaluate function other than the
+// built in one of the same name. The history does not hold their rules, so the round
+// can not be replayed.
+type RoundHistory struct {
+	Round          int           `json:"round"`
+	Rules          Rules         `json:"rules"`
+	Seed           *int64        `json:"seed,omitempty"`
+	Shuffles       int           `json:"shuffles"`
+	ShoeDealt      int           `json:"shoeDealt"`
+	Shoe           []deck.Card   `json:"shoe"`
+	CustomDeck     bool          `json:"customDeck,omitempty"`
+	Reshuffled     [][]deck.Card `json:"reshuffled,omitempty"`
+	Seats          int           `json:"seats"`
+	SideBets       []SideBet     `json:"sideBets,omitempty"`
+	CustomSideBets []string      `json:"customSideBets,omitempty"`
+	Players        []PlayerEntry `json:"players"`
+	Events         Events        `json:"events"`
+}
+
+// PlayerEntry is a player seated when a round started.
+type PlayerEntry struct {
+	Seat    int      `json:"seat"`
+	Name    string   `json:"name"`
+	Balance *float64 `json:"balance,omitempty"`
+}
+
+// Events is a list of events encoded in JSON with MarshalEvent.
+type Events []Event
+
+func (evs Events) MarshalJSON() ([]byte, error) {
+	raw := make([]json.RawMessage, len(evs))
+	for i, ev := range evs {
+		data, err := MarshalEvent(ev)
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = data
+	}
+	return json.Marshal(raw)
+}
+
+func (evs *Events) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*evs = make(Events, len(raw))
+	for i, r := range raw {
+		ev, err := UnmarshalEvent(r)
+		if err != nil {
+			return err
+		}
+		(*evs)[i] = ev
+	}
+	return nil
+}
+
+// Recorder writes the history of every round an engine plays as JSON Lines,
+// one RoundHistory per line.
+type Recorder struct {
+	engine  *Engine
+	enc     *json.Encoder
+	current *RoundHistory
+	err     error
+}
+
+// NewRecorder starts recording the rounds of the engine to w, from the next round on.
+func NewRecorder(e *Engine, w io.Writer) *Recorder {
+	r := &Recorder{engine: e, enc: json.NewEncoder(w)}
+	e.AddListener(r.listen)
+	return r
+}
+
+// Err returns the first error writing the history.
+func (r *Recorder) Err() error {
+	return r.err
+}
+
+func (r *Recorder) listen(ev Event) {
+	e := r.engine
+	if _, ok := ev.(RoundStarted); ok {
+		r.current = &RoundHistory{
+			Round:      e.round,
+			Rules:      e.rules,
+			Seed:       e.seed,
+			Shuffles:   e.shuffles,
+			ShoeDealt:  e.shoe.Dealt(),
+			CustomDeck: e.customDeck,
+			Shoe:       e.shoe.Cards()[e.shoe.Dealt():],
+			Seats:      len(e.seats),
+			SideBets:   e.sideBets,
+		}
+		for _, b := range e.sideBets {
+			if !b.builtin() {
+				r.current.CustomSideBets = append(r.current.CustomSideBets, b.Name)
+			}
+		}
+		for _, s := range e.State().Seats {
+			r.current.Players = append(r.current.Players, PlayerEntry{Seat: s.Seat, Name: s.Name, Balance: s.Balance})
+		}
+	}
+	if r.current == nil {
+		return
+	}
+
+	r.current.Events = append(r.current.Events, ev)
+	if _, ok := ev.(ShoeShuffled); ok {
+		r.current.Reshuffled = append(r.current.Reshuffled, e.shoe.Cards())
+	}
+	if _, ok := ev.(RoundSettled); ok {
+		if err := r.enc.Encode(r.current); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.current = nil
+	}
+}
+
+// NewShoe builds the shoe the round was dealt from out of the seed, positioned at the
+// start of the round. It fails when the engine was not shuffled with a seed or was
+// given deck options, which the history does not hold.
+func (h RoundHistory) NewShoe() (*deck.Shoe, error) {
+	if h.Seed == nil {
+		return nil, fmt.Errorf("blackjack: round %d was not dealt from a seeded shoe", h.Round)
+	}
+	if h.CustomDeck {
+		return nil, fmt.Errorf("blackjack: round %d was dealt from a shoe built with deck options", h.Round)
+	}
+	shoe, err := deck.NewShoe(h.Rules.Decks, h.Rules.Penetration, deck.WithShuffle(), deck.WithSeed(*h.Seed))
+	if err != nil {
+		return nil, err
+	}
+	for range h.Shuffles {
+		shoe.Shuffle()
+	}
+	for range h.ShoeDealt {
+		shoe.Draw()
+	}
+	return shoe, nil
+}
+
+// ReadHistory reads the rounds of a hand history written by a Recorder.
+func ReadHistory(r io.Reader) ([]RoundHistory, error) {
+	var rounds []RoundHistory
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var h RoundHistory
+		if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+			return nil, fmt.Errorf("blackjack: hand history line %d: %w", line, err)
+		}
+		rounds = append(rounds, h)
+	}
+	return rounds, scanner.Err()
+}
+
+// Replay plays a recorded round again on a new engine, dealing from the recorded shoe
+// and making the recorded decisions. Only rounds offering the side bets built in,
+// with any pay table, can be replayed, a custom side bet is rejected even when named
+// like a built in one. ErrReplayMismatch is returned, describing the
+// first difference, when the engine does not reproduce the recorded events.
+func Replay(h RoundHistory) error {
+	return replay(h, nil)
+}
+
+// replay is Replay calling before, when set, with the engine ahead of every recorded
+// event the engine is driven by.
+func replay(h RoundHistory, before func(e *Engine, ev Event)) error {
+	shoe, err := deck.NewShoeFromCards(h.Shoe, h.Rules.Decks, 1)
+	if err != nil {
+		return err
+	}
+	if len(h.CustomSideBets) > 0 {
+		return fmt.Errorf("blackjack: round %d offers custom side bet %q which can not be replayed", h.Round, h.CustomSideBets[0])
+	}
+	sideBets := slices.Clone(h.SideBets)
+	for i, b := range sideBets {
+		if sideBets[i].Evaluate = builtinSideBets[b.Name]; sideBets[i].Evaluate == nil {
+			return fmt.Errorf("blackjack: round %d offers side bet %q which can not be replayed", h.Round, b.Name)
+		}
+	}
+	e, err := NewEngine(h.Rules, WithShoe(shoe), WithSeats(h.Seats), WithSideBets(sideBets...))
+	if err != nil {
+		return err
+	}
+	if err := seatPlayers(e, h.Players); err != nil {
+		return err
+	}
+	e.round = h.Round - 1
+	for _, cards := range h.Reshuffled {
+		shoe, err := deck.NewShoeFromCards(cards, h.Rules.Decks, h.Rules.Penetration)
+		if err != nil {
+			return err
+		}
+		e.reshuffled = append(e.reshuffled, shoe)
+	}
+
+	var replayed Events
+	settled := false
+	e.AddListener(func(ev Event) {
+		// Players leaving broke after the round are not part of it.
+		if settled {
+			return
+		}
+		replayed = append(replayed, ev)
+		_, settled = ev.(RoundSettled)
+	})
+
+	for i, ev := range h.Events {
+		if i < len(replayed) {
+			// Already reproduced by the engine.
+			continue
+		}
+		if before != nil {
+			before(e, ev)
+		}
+		var err error
+		switch ev := ev.(type) {
+		case RoundStarted:
+			err = e.NewRound()
+		case BetPlaced:
+			err = e.PlaceBet(ev.Seat, ev.Amount)
+		case BetAdjusted:
+			// Only told by PlayRound, which replays do not use.
+			e.emit(ev)
+		case SideBetPlaced:
+			err = e.PlaceSideBet(ev.Seat, ev.Name, ev.Amount)
+		case CardDealt:
+			err = e.Deal()
+		case InsuranceDecided:
+			err = e.Insure(ev.Seat, ev.Taken)
+		case PlayerActed:
+			err = e.Act(ev.Seat, ev.Action)
+		case PlayerJoined:
+			// Players joining during the round play from the next one, so their balance does not matter.
+			var seat int
+			if seat, err = e.AddPlayer(ev.Name, nil); err == nil && seat != ev.Seat {
+				err = fmt.Errorf("seated at %d", seat)
+			}
+		case PlayerLeft:
+			err = e.RemovePlayer(ev.Seat)
+		default:
+			return fmt.Errorf("%w: round %d event %d: expected %s, got nothing", ErrReplayMismatch, h.Round, i, EventType(ev))
+		}
+		if err != nil {
+			return fmt.Errorf("%w: round %d event %d: %s failed: %v", ErrReplayMismatch, h.Round, i, EventType(ev), err)
+		}
+	}
+
+	for i := range max(len(h.Events), len(replayed)) {
+		switch {
+		case i >= len(replayed):
+			return fmt.Errorf("%w: round %d event %d: expected %s, got nothing", ErrReplayMismatch, h.Round, i, EventType(h.Events[i]))
+		case i >= len(h.Events):
+			return fmt.Errorf("%w: round %d event %d: got unexpected %s", ErrReplayMismatch, h.Round, i, EventType(replayed[i]))
+		case !reflect.DeepEqual(h.Events[i], replayed[i]):
+			return fmt.Errorf("%w: round %d event %d: expected %+v, got %+v", ErrReplayMismatch, h.Round, i, h.Events[i], replayed[i])
+		}
+	}
+	return nil
+}
+
+// seatPlayers seats the players, sorted by seat, at their recorded seats. Free seats
+// before a player are filled while seating, then emptied again.
+func seatPlayers(e *Engine, players []PlayerEntry) error {
+	var fillers []int
+	for _, p := range players {
+		var options []PlayerOptionFunc
+		if p.Balance != nil {
+			options = append(options, WithBalance(*p.Balance))
+		}
+		for {
+			seat, err := e.AddPlayer(p.Name, nil, options...)
+			if err != nil {
+				return err
+			}
+			if seat == p.Seat {
+				break
+			}
+			fillers = append(fillers, seat)
+		}
+	}
+	for _, seat := range fillers {
+		if err := e.RemovePlayer(seat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
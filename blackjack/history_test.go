@@ -0,0 +1,173 @@
+package blackjack
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func recordRounds(t *testing.T, rounds int) []RoundHistory {
+	t.Helper()
+	rules := DefaultRules()
+	rules.Decks = 2
+
+	e, err := NewEngine(rules, WithShoeSeed(7), WithSeats(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r := NewRecorder(e, &buf)
+	e.AddPlayer("Basic", NewBasicStrategy(rules, 10), WithBalance(1000))
+	e.AddPlayer("Filler", nil)
+	e.AddPlayer("Mimic", scriptedAI{hitBelow: 17, insurance: true, bet: 5})
+	e.RemovePlayer(1)
+
+	for range rounds {
+		if err := e.PlayRound(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+
+	history, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != rounds {
+		t.Fatalf("expected %d rounds of history, got %d", rounds, len(history))
+	}
+	return history
+}
+
+func TestReplay(t *testing.T) {
+	history := recordRounds(t, 100)
+
+	shuffled := false
+	for _, h := range history {
+		if err := Replay(h); err != nil {
+			t.Fatal(err)
+		}
+
+		shoe, err := h.NewShoe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := shoe.Cards()[shoe.Dealt():]; !reflect.DeepEqual(got, h.Shoe) {
+			t.Fatalf("expected the seed to rebuild the shoe of round %d", h.Round)
+		}
+		shuffled = shuffled || h.Shuffles > 0
+	}
+	if !shuffled {
+		t.Fatal("expected the shoe to be reshuffled during the history")
+	}
+	if p := history[0].Players; len(p) != 2 || p[1].Seat != 2 || *p[0].Balance != 1000 {
+		t.Fatalf("unexpected players %+v", p)
+	}
+
+	// The seed alone does not rebuild a shoe built with deck options.
+	e, err := NewEngine(DefaultRules(), WithShoeSeed(7), WithDeckOptions(deck.WithFilter(func(c deck.Card) bool { return c.Type == deck.TWO })))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r := NewRecorder(e, &buf)
+	e.AddPlayer("Mimic", scriptedAI{hitBelow: 17, bet: 5})
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	custom, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := custom[0].NewShoe(); err == nil {
+		t.Fatal("expected a shoe built with deck options not to be rebuilt from the seed")
+	}
+}
+
+func TestReplayReshuffledMidRound(t *testing.T) {
+	// Hitting from 6 runs the four card shoe out again and again.
+	stacked, _ := deck.ParseDeck("2s 3s 2h 3h")
+	shoe, _ := deck.NewShoeFromCards(stacked, 1, 1)
+	rules := DefaultRules()
+	rules.Decks = 1
+	e, err := NewEngine(rules, WithShoe(shoe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r := NewRecorder(e, &buf)
+	e.AddPlayer("Mimic", scriptedAI{hitBelow: 17, bet: 5})
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	history, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := history[0]
+	if len(h.Reshuffled) < 2 {
+		t.Fatalf("expected the shoe to run out during the round, got %d reshuffles", len(h.Reshuffled))
+	}
+	if err := Replay(h); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplaySeatChanges(t *testing.T) {
+	e := stackedEngine(t, "Ts 5s 7c 6h 9d Kd 2c")
+	var buf bytes.Buffer
+	r := NewRecorder(e, &buf)
+	alice, _ := e.AddPlayer("Alice", nil)
+	bob, _ := e.AddPlayer("Bob", nil)
+
+	// Bob sits the round out and leaves, Carol joins while Alice plays.
+	e.NewRound()
+	e.PlaceBet(alice, 10)
+	e.Deal()
+	if err := e.RemovePlayer(bob); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddPlayer("Carol", nil, WithBalance(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Act(alice, Stand); err != nil {
+		t.Fatal(err)
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+
+	history, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Replay(history[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	h := recordRounds(t, 1)[0]
+
+	for i, ev := range h.Events {
+		if settled, ok := ev.(RoundSettled); ok {
+			settled.Results[0].Net += 1
+			h.Events[i] = settled
+		}
+	}
+	if err := Replay(h); !errors.Is(err, ErrReplayMismatch) {
+		t.Fatalf("expected ErrReplayMismatch for a changed payout, got %v", err)
+	}
+}
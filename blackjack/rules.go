@@ -0,0 +1,160 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DoubleRule decides which two card hands a player is allowed to double down on.
+type DoubleRule uint8
+
+const (
+	// DoubleAny allows doubling on any two cards.
+	DoubleAny DoubleRule = iota
+	// DoubleNineToEleven allows doubling on two cards totalling 9, 10 or 11.
+	DoubleNineToEleven
+	// DoubleTenOrEleven allows doubling on two cards totalling 10 or 11.
+	DoubleTenOrEleven
+)
+
+// Payout is the ratio paid on a winning bet, e.g Payout{3, 2} pays 3 for every 2 bet.
+type Payout struct {
+	Win int `json:"win"`
+	Bet int `json:"bet"`
+}
+
+// Of returns the amount paid for the bet.
+func (p Payout) Of(bet float64) float64 {
+	return bet * float64(p.Win) / float64(p.Bet)
+}
+
+func (p Payout) String() string {
+	return fmt.Sprintf("%d:%d", p.Win, p.Bet)
+}
+
+// Rules configures a blackjack table. Use DefaultRules as a starting point
+// and change the rules that differ.
+//
+// Decks: number of decks in the shoe.
+//
+// Penetration: fraction of the shoe dealt before the cut card and a reshuffle.
+//
+// BlackjackPayout: what a natural blackjack pays, usually 3:2 or 6:5.
+//
+// DealerHitsSoft17: whether the dealer hits soft 17 (H17) or stands on it (S17).
+//
+// DoubleOn: which two card totals can be doubled.
+//
+// DoubleAfterSplit: whether hands made by splitting a pair can be doubled.
+//
+// Surrender: whether late surrender is offered, giving up half the bet after the dealer checks for blackjack.
+//
+// MaxSplits: how many times a player can split in one round, so a player holds up to MaxSplits+1 hands.
+//
+// ResplitAces: whether split aces can be split again.
+//
+// HitSplitAces: whether split aces can draw more than one card.
+//
+// MinBet, MaxBet: the table limits, 0 for no limit.
+type Rules struct {
+	Decks            int        `json:"decks"`
+	Penetration      float64    `json:"penetration"`
+	BlackjackPayout  Payout     `json:"blackjackPayout"`
+	DealerHitsSoft17 bool       `json:"dealerHitsSoft17"`
+	DoubleOn         DoubleRule `json:"doubleOn"`
+	DoubleAfterSplit bool       `json:"doubleAfterSplit"`
+	Surrender        bool       `json:"surrender"`
+	MaxSplits        int        `json:"maxSplits"`
+	ResplitAces      bool       `json:"resplitAces"`
+	HitSplitAces     bool       `json:"hitSplitAces"`
+	MinBet           int        `json:"minBet"`
+	MaxBet           int        `json:"maxBet"`
+}
+
+// DefaultRules returns a common six deck shoe game: blackjack pays 3:2, the dealer hits
+// soft 17, doubling is allowed on any two cards including after splits, late
+// surrender is offered and pairs can be split up to 3 times except aces.
+func DefaultRules() Rules {
+	return Rules{
+		Decks:            6,
+		Penetration:      0.75,
+		BlackjackPayout:  Payout{3, 2},
+		DealerHitsSoft17: true,
+		DoubleOn:         DoubleAny,
+		DoubleAfterSplit: true,
+		Surrender:        true,
+		MaxSplits:        3,
+		ResplitAces:      false,
+		HitSplitAces:     false,
+	}
+}
+
+// Validate returns an error describing the first invalid rule.
+func (r Rules) Validate() error {
+	switch {
+	case r.Decks < 1:
+		return fmt.Errorf("blackjack: at least one deck is needed, got %d", r.Decks)
+	case r.Penetration <= 0 || r.Penetration > 1:
+		return fmt.Errorf("blackjack: penetration must be in (0, 1], got %v", r.Penetration)
+	case r.BlackjackPayout.Win < 1 || r.BlackjackPayout.Bet < 1:
+		return fmt.Errorf("blackjack: invalid blackjack payout %s", r.BlackjackPayout)
+	case r.DoubleOn > DoubleTenOrEleven:
+		return fmt.Errorf("blackjack: unknown double rule %d", r.DoubleOn)
+	case r.MaxSplits < 0:
+		return errors.New("blackjack: max splits can not be negative")
+	case r.MinBet < 0 || r.MaxBet < 0:
+		return errors.New("blackjack: table limits can not be negative")
+	case r.MaxBet > 0 && r.MaxBet < r.MinBet:
+		return fmt.Errorf("blackjack: maximum bet %d is below the minimum bet %d", r.MaxBet, r.MinBet)
+	}
+	return nil
+}
+
+// DealerStrategy returns the dealer strategy for the soft 17 rule.
+func (r Rules) DealerStrategy() DealerStrategy {
+	if r.DealerHitsSoft17 {
+		return StandardDealerStrategy{}
+	}
+	return Soft17StandDealerStrategy{}
+}
+
+// canDouble reports whether a two card total can be doubled.
+func (r Rules) canDouble(total int) bool {
+	switch r.DoubleOn {
+	case DoubleNineToEleven:
+		return total >= 9 && total <= 11
+	case DoubleTenOrEleven:
+		return total == 10 || total == 11
+	}
+	return true
+}
+
+func (r Rules) String() string {
+	soft17 := "S17"
+	if r.DealerHitsSoft17 {
+		soft17 = "H17"
+	}
+	s := fmt.Sprintf("%d decks, %s, blackjack pays %s", r.Decks, soft17, r.BlackjackPayout)
+	switch {
+	case r.MinBet > 0 && r.MaxBet > 0:
+		s += fmt.Sprintf(", bets %d to %d", r.MinBet, r.MaxBet)
+	case r.MinBet > 0:
+		s += fmt.Sprintf(", minimum bet %d", r.MinBet)
+	case r.MaxBet > 0:
+		s += fmt.Sprintf(", maximum bet %d", r.MaxBet)
+	}
+	return s
+}
+
+// checkBet returns an error if the bet is outside of the table limits.
+func (r Rules) checkBet(amount int) error {
+	switch {
+	case amount <= 0:
+		return fmt.Errorf("%w: %d", ErrInvalidBet, amount)
+	case amount < r.MinBet:
+		return fmt.Errorf("%w: %d is below the table minimum of %d", ErrInvalidBet, amount, r.MinBet)
+	case r.MaxBet > 0 && amount > r.MaxBet:
+		return fmt.Errorf("%w: %d is above the table maximum of %d", ErrInvalidBet, amount, r.MaxBet)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+)
+
+// Client is a connection to a Server sending a JSON message per line.
+type Client struct {
+	conn net.Conn
+	dec  *json.Decoder
+	enc  *json.Encoder
+}
+
+// Dial connects to a server listening on a TCP address.
+func Dial(addr string) (*Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient returns a client talking to a server over c.
+func NewClient(c net.Conn) *Client {
+	return &Client{conn: c, dec: json.NewDecoder(c), enc: json.NewEncoder(c)}
+}
+
+func (c *Client) Send(r Request) error {
+	return c.enc.Encode(r)
+}
+
+// Receive waits for the next message from the server.
+func (c *Client) Receive() (Message, error) {
+	var m Message
+	err := c.dec.Decode(&m)
+	return m, err
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) Join(name string) error {
+	return c.Send(Request{Type: RequestJoin, Name: name})
+}
+
+func (c *Client) Resume(token string) error {
+	return c.Send(Request{Type: RequestResume, Token: token})
+}
+
+func (c *Client) Bet(amount int) error {
+	return c.Send(Request{Type: RequestBet, Amount: amount})
+}
+
+func (c *Client) Insure(take bool) error {
+	return c.Send(Request{Type: RequestInsurance, Take: take})
+}
+
+func (c *Client) Act(action blackjack.Action) error {
+	return c.Send(Request{Type: RequestAction, Action: &action})
+}
+
+func (c *Client) Leave() error {
+	return c.Send(Request{Type: RequestLeave})
+}
+
+// DecodeEvent returns the event carried by a MessageEvent.
+func (m Message) DecodeEvent() (blackjack.Event, error) {
+	return blackjack.UnmarshalEvent(m.Event)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// conn sends and receives the messages of one connection.
+type conn interface {
+	Receive(*Request) error
+	Send(Message) error
+	Close() error
+}
+
+// lineConn sends a JSON object per line.
+type lineConn struct {
+	c   net.Conn
+	dec *json.Decoder
+	enc *json.Encoder
+}
+
+func newLineConn(c net.Conn) *lineConn {
+	return &lineConn{c: c, dec: json.NewDecoder(c), enc: json.NewEncoder(c)}
+}
+
+func (c *lineConn) Receive(r *Request) error { return c.dec.Decode(r) }
+func (c *lineConn) Send(m Message) error     { return c.enc.Encode(m) }
+func (c *lineConn) Close() error             { return c.c.Close() }
+
+// wsConn sends a JSON object per WebSocket frame.
+type wsConn struct {
+	ws *websocket.Conn
+}
+
+func (c wsConn) Receive(r *Request) error { return websocket.JSON.Receive(c.ws, r) }
+func (c wsConn) Send(m Message) error     { return websocket.JSON.Send(c.ws, m) }
+func (c wsConn) Close() error             { return c.ws.Close() }
+
+// WebSocket returns a handler serving WebSocket connections.
+func (s *Server) WebSocket() http.Handler {
+	return websocket.Handler(func(ws *websocket.Conn) {
+		s.serve(wsConn{ws})
+	})
+}
+
+// client is a connection to the table, seated or watching.
+type client struct {
+	conn   conn
+	out    chan Message
+	player *player
+	once   sync.Once
+}
+
+// send queues a message, a client that does not keep up is disconnected.
+func (c *client) send(m Message) {
+	select {
+	case c.out <- m:
+	default:
+		c.conn.Close()
+	}
+}
+
+func (c *client) write() {
+	for m := range c.out {
+		if err := c.conn.Send(m); err != nil {
+			c.conn.Close()
+		}
+	}
+}
+
+// close stops writing to the client, it is only called by the table goroutine.
+func (c *client) close() {
+	c.once.Do(func() {
+		close(c.out)
+		c.conn.Close()
+	})
+}
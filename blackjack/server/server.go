@@ -0,0 +1,567 @@
+// Package server runs a blackjack table that remote players join over TCP or
+// WebSocket. Every message is a JSON object, one per line over TCP and one per
+// frame over WebSocket.
+//
+// Every connection watches the table as a spectator, receiving the events and a
+// snapshot of the table after every change, until it sends a join request to take a
+// seat. Players are given a token to resume their seat after losing the connection.
+// A player who does not act before the deadline sent with the table state stands,
+// declines insurance or sits the round out.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+)
+
+// Request types sent by clients.
+const (
+	// RequestJoin takes a seat at the table under Name.
+	RequestJoin = "join"
+	// RequestResume takes back the seat of the player with Token.
+	RequestResume = "resume"
+	// RequestBet bets Amount for the round, 0 sits the round out.
+	RequestBet = "bet"
+	// RequestInsurance takes insurance, or even money, when Take is set.
+	RequestInsurance = "insurance"
+	// RequestAction plays Action on the hand whose turn it is.
+	RequestAction = "action"
+	// RequestLeave gives up the seat once the current round is over.
+	RequestLeave = "leave"
+)
+
+// Message types sent by the server.
+const (
+	// MessageWelcome confirms a join or resume request with the Seat and Token of the player.
+	MessageWelcome = "welcome"
+	// MessageEvent carries an Event encoded with blackjack.MarshalEvent.
+	MessageEvent = "event"
+	// MessageState carries a snapshot of the table and the Deadline of the current decision.
+	MessageState = "state"
+	// MessageError reports why a request failed.
+	MessageError = "error"
+)
+
+// Request is a message sent by a client to the server.
+type Request struct {
+	Type   string            `json:"type"`
+	Name   string            `json:"name,omitempty"`
+	Token  string            `json:"token,omitempty"`
+	Amount int               `json:"amount,omitempty"`
+	Take   bool              `json:"take,omitempty"`
+	Action *blackjack.Action `json:"action,omitempty"`
+}
+
+// Message is a message sent by the server to a client.
+type Message struct {
+	Type     string           `json:"type"`
+	Seat     *int             `json:"seat,omitempty"`
+	Token    string           `json:"token,omitempty"`
+	Event    json.RawMessage  `json:"event,omitempty"`
+	State    *blackjack.State `json:"state,omitempty"`
+	Deadline *time.Time       `json:"deadline,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+var (
+	ErrClosed    = errors.New("server: closed")
+	ErrNotSeated = errors.New("server: connection has no seat")
+	ErrSeated    = errors.New("server: connection already has a seat")
+	ErrToken     = errors.New("server: unknown token")
+)
+
+// Type Options is used to configure New.
+//
+// TurnTimeout: time a player has to decide on insurance or act on a hand. Defaults
+// to 30 seconds. Set with WithTurnTimeout function
+//
+// BetTimeout: time players have to bet before the cards are dealt. Defaults to 30
+// seconds. Set with WithBetTimeout function
+//
+// ReconnectTimeout: time a disconnected player keeps the seat. Defaults to 2 minutes.
+// Set with WithReconnectTimeout function
+//
+// EngineOptions: options used to create the table's engine. Set with WithEngineOptions function
+type Options struct {
+	TurnTimeout      time.Duration
+	BetTimeout       time.Duration
+	ReconnectTimeout time.Duration
+	EngineOptions    []blackjack.EngineOptionFunc
+}
+
+// type OptionFunc acts a wrapper for functional
+// options used for configuration in New
+type OptionFunc func(*Options)
+
+// Option that sets the time a player has to act before standing automatically.
+func WithTurnTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.TurnTimeout = d
+	}
+}
+
+// Option that sets the time players have to bet.
+func WithBetTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.BetTimeout = d
+	}
+}
+
+// Option that sets the time a disconnected player keeps the seat.
+func WithReconnectTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.ReconnectTimeout = d
+	}
+}
+
+// Option used to pass options to the table's engine, e.g blackjack.WithSeats.
+func WithEngineOptions(options ...blackjack.EngineOptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.EngineOptions = append(o.EngineOptions, options...)
+	}
+}
+
+// Server runs one table. The engine is only used by the goroutine started in New,
+// connections hand their requests over to it.
+type Server struct {
+	engine   *blackjack.Engine
+	options  Options
+	requests chan request
+	closed   chan struct{}
+	close    sync.Once
+
+	// Owned by the table goroutine.
+	clients  map[*client]bool
+	players  map[string]*player
+	waiting  string
+	deadline time.Time
+}
+
+// player is a seated player, who may be disconnected.
+type player struct {
+	token        string
+	name         string
+	seat         int
+	client       *client
+	disconnected time.Time
+	decided      bool
+	bet          bool
+	leaving      bool
+}
+
+// request is a request of a client handed over to the table goroutine.
+type request struct {
+	client     *client
+	req        Request
+	connect    bool
+	disconnect bool
+}
+
+// New creates a server for a table with the rules and starts running it.
+func New(rules blackjack.Rules, options ...OptionFunc) (*Server, error) {
+	o := Options{
+		TurnTimeout:      30 * time.Second,
+		BetTimeout:       30 * time.Second,
+		ReconnectTimeout: 2 * time.Minute,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+
+	s := &Server{
+		options:  o,
+		requests: make(chan request),
+		closed:   make(chan struct{}),
+		clients:  map[*client]bool{},
+		players:  map[string]*player{},
+	}
+	engine, err := blackjack.NewEngine(rules, append(o.EngineOptions, blackjack.WithListener(s.broadcastEvent))...)
+	if err != nil {
+		return nil, err
+	}
+	s.engine = engine
+
+	go s.run()
+	return s, nil
+}
+
+// Serve accepts TCP connections until the listener fails or the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	go func() {
+		<-s.closed
+		l.Close()
+	}()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+				return ErrClosed
+			default:
+				return err
+			}
+		}
+		go s.ServeConn(c)
+	}
+}
+
+// ServeConn serves a single connection, sending a JSON message per line, until it is closed.
+func (s *Server) ServeConn(c net.Conn) {
+	s.serve(newLineConn(c))
+}
+
+// Close stops the table and closes every connection.
+func (s *Server) Close() error {
+	s.close.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (s *Server) serve(c conn) {
+	cl := &client{conn: c, out: make(chan Message, 256)}
+	if !s.send(request{client: cl, connect: true}) {
+		c.Close()
+		return
+	}
+	go cl.write()
+
+	for {
+		var req Request
+		if err := c.Receive(&req); err != nil {
+			break
+		}
+		if !s.send(request{client: cl, req: req}) {
+			break
+		}
+	}
+	c.Close()
+	s.send(request{client: cl, disconnect: true})
+}
+
+// send hands a request over to the table goroutine, it reports false once the server is closed.
+func (s *Server) send(r request) bool {
+	select {
+	case s.requests <- r:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
+
+func (s *Server) run() {
+	defer func() {
+		for c := range s.clients {
+			c.close()
+		}
+	}()
+
+	for {
+		var timeout <-chan time.Time
+		if !s.deadline.IsZero() {
+			timeout = time.After(time.Until(s.deadline))
+		}
+
+		select {
+		case r := <-s.requests:
+			s.handle(r)
+		case <-timeout:
+			s.expire()
+		case <-s.closed:
+			return
+		}
+		s.step()
+		s.broadcastState()
+	}
+}
+
+func (s *Server) handle(r request) {
+	c := r.client
+	switch {
+	case r.connect:
+		s.clients[c] = true
+		return
+	case r.disconnect:
+		delete(s.clients, c)
+		c.close()
+		if p := c.player; p != nil && p.client == c {
+			p.client = nil
+			p.disconnected = time.Now()
+		}
+		return
+	}
+
+	decides := s.decides(c, r.req)
+	if err := s.do(c, r.req); err != nil {
+		c.send(Message{Type: MessageError, Error: err.Error()})
+		return
+	}
+	// Making the decision waited for starts a new deadline, other requests must not
+	// put it off.
+	if decides {
+		s.waiting = ""
+	}
+}
+
+// decides reports whether the request makes a decision the table waits for: the bet
+// of a player who did not decide yet, or the insurance or action of the pending seat.
+func (s *Server) decides(c *client, req Request) bool {
+	p := c.player
+	if p == nil {
+		return false
+	}
+	switch req.Type {
+	case RequestBet:
+		return !p.decided
+	case RequestInsurance:
+		seat, ok := s.engine.PendingInsurance()
+		return ok && seat == p.seat
+	case RequestAction:
+		seat, _, ok := s.engine.Turn()
+		return ok && seat == p.seat
+	}
+	return false
+}
+
+// do carries out the request of a client.
+func (s *Server) do(c *client, req Request) error {
+	switch req.Type {
+	case RequestJoin:
+		if c.player != nil {
+			return ErrSeated
+		}
+		if req.Name == "" {
+			return errors.New("server: a name is needed to join")
+		}
+		token, err := newToken()
+		if err != nil {
+			return err
+		}
+		seat, err := s.engine.AddPlayer(req.Name, nil)
+		if err != nil {
+			return err
+		}
+		// Joining while bets are taken gives a chance to bet, otherwise the player plays from the next round.
+		p := &player{token: token, name: req.Name, seat: seat, client: c}
+		p.decided = s.engine.Phase() != blackjack.PhaseBetting
+		s.players[p.token] = p
+		c.player = p
+		c.send(Message{Type: MessageWelcome, Seat: &p.seat, Token: p.token})
+		return nil
+
+	case RequestResume:
+		p, ok := s.players[req.Token]
+		if !ok {
+			return ErrToken
+		}
+		if c.player != nil && c.player != p {
+			return ErrSeated
+		}
+		if p.client != nil && p.client != c {
+			// The old connection is taken over.
+			p.client.player = nil
+		}
+		p.client, c.player = c, p
+		c.send(Message{Type: MessageWelcome, Seat: &p.seat, Token: p.token})
+		return nil
+	}
+
+	p := c.player
+	if p == nil {
+		return ErrNotSeated
+	}
+	switch req.Type {
+	case RequestBet:
+		if req.Amount > 0 {
+			if err := s.engine.PlaceBet(p.seat, req.Amount); err != nil {
+				return err
+			}
+			p.bet = true
+		} else if s.engine.Phase() != blackjack.PhaseBetting {
+			return blackjack.ErrWrongPhase
+		}
+		p.decided = true
+		return nil
+	case RequestInsurance:
+		return s.engine.Insure(p.seat, req.Take)
+	case RequestAction:
+		if req.Action == nil {
+			return errors.New("server: no action given")
+		}
+		return s.engine.Act(p.seat, *req.Action)
+	case RequestLeave:
+		p.leaving, p.client, c.player = true, nil, nil
+		s.dropPlayers()
+		return nil
+	}
+	return fmt.Errorf("server: unknown request type %q", req.Type)
+}
+
+// step moves the table on as far as it can go without a decision, then starts
+// the deadline of the decision it waits for.
+func (s *Server) step() {
+	for {
+		switch s.engine.Phase() {
+		case blackjack.PhaseIdle:
+			s.dropPlayers()
+			if len(s.players) == 0 {
+				s.wait("")
+				return
+			}
+			s.engine.NewRound()
+			for _, p := range s.players {
+				p.decided, p.bet = p.client == nil, false
+			}
+
+		case blackjack.PhaseBetting:
+			if !s.betsIn() {
+				s.wait(fmt.Sprintf("bet %d", s.engine.State().Round))
+				return
+			}
+			s.engine.Deal()
+
+		case blackjack.PhaseInsurance:
+			seat, _ := s.engine.PendingInsurance()
+			s.wait(fmt.Sprintf("insurance %d", seat))
+			return
+
+		case blackjack.PhasePlayerTurn:
+			seat, hand, _ := s.engine.Turn()
+			s.wait(fmt.Sprintf("turn %d %d", seat, hand))
+			return
+		}
+	}
+}
+
+// betsIn reports whether every connected player decided on a bet and there is at least one bet.
+func (s *Server) betsIn() bool {
+	bets := false
+	for _, p := range s.players {
+		if !p.decided {
+			return false
+		}
+		bets = bets || p.bet
+	}
+	return bets
+}
+
+// wait starts the deadline of a decision, unless it already runs. An empty key stops waiting.
+func (s *Server) wait(key string) {
+	if key == s.waiting {
+		return
+	}
+	s.waiting = key
+
+	timeout := s.options.TurnTimeout
+	switch {
+	case key == "":
+		s.deadline = time.Time{}
+		return
+	case s.engine.Phase() == blackjack.PhaseBetting:
+		timeout = s.options.BetTimeout
+	}
+	s.deadline = time.Now().Add(timeout)
+}
+
+// expire makes the decision the table waits for once its deadline passed.
+func (s *Server) expire() {
+	s.waiting = ""
+	switch s.engine.Phase() {
+	case blackjack.PhaseBetting:
+		s.dropPlayers()
+		bets := false
+		for _, p := range s.players {
+			bets = bets || p.bet
+		}
+		if bets {
+			s.engine.Deal()
+			return
+		}
+		// Nobody bet, wait for bets again.
+		for _, p := range s.players {
+			p.decided = p.client == nil
+		}
+	case blackjack.PhaseInsurance:
+		seat, _ := s.engine.PendingInsurance()
+		s.engine.Insure(seat, false)
+	case blackjack.PhasePlayerTurn:
+		seat, _, _ := s.engine.Turn()
+		s.engine.Act(seat, blackjack.Stand)
+	}
+}
+
+// dropPlayers frees the seats of players who left or did not come back in time,
+// once they are not playing a round.
+func (s *Server) dropPlayers() {
+	for token, p := range s.players {
+		gone := p.client == nil && time.Since(p.disconnected) >= s.options.ReconnectTimeout
+		if !p.leaving && !gone {
+			continue
+		}
+		if err := s.engine.RemovePlayer(p.seat); err != nil && !errors.Is(err, blackjack.ErrEmptySeat) {
+			continue
+		}
+		if p.client != nil {
+			p.client.player = nil
+		}
+		delete(s.players, token)
+	}
+}
+
+// Connect returns a client connected to the server in process.
+func (s *Server) Connect() *Client {
+	client, server := net.Pipe()
+	go s.ServeConn(server)
+	return NewClient(client)
+}
+
+func (s *Server) broadcastEvent(ev blackjack.Event) {
+	// A player who went broke was removed by the engine.
+	if left, ok := ev.(blackjack.PlayerLeft); ok && left.Broke {
+		for token, p := range s.players {
+			if p.seat == left.Seat {
+				if p.client != nil {
+					p.client.player = nil
+				}
+				delete(s.players, token)
+			}
+		}
+	}
+
+	data, err := blackjack.MarshalEvent(ev)
+	if err != nil {
+		return
+	}
+	s.broadcast(Message{Type: MessageEvent, Event: data})
+}
+
+func (s *Server) broadcastState() {
+	st := s.engine.State()
+	m := Message{Type: MessageState, State: &st}
+	if !s.deadline.IsZero() {
+		deadline := s.deadline
+		m.Deadline = &deadline
+	}
+	s.broadcast(m)
+}
+
+func (s *Server) broadcast(m Message) {
+	for c := range s.clients {
+		c.send(m)
+	}
+}
+
+// newToken returns a random token, the only credential needed to take a seat back.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+	"github.com/Junior-Green/gophercises/deck"
+	"golang.org/x/net/websocket"
+)
+
+// testClient reads the messages of a client in the background.
+type testClient struct {
+	*Client
+	messages chan Message
+}
+
+func connect(t *testing.T, c *Client) *testClient {
+	t.Helper()
+	tc := &testClient{Client: c, messages: make(chan Message, 1024)}
+	go func() {
+		defer close(tc.messages)
+		for {
+			m, err := c.Receive()
+			if err != nil {
+				return
+			}
+			tc.messages <- m
+		}
+	}()
+	t.Cleanup(func() { c.Close() })
+	return tc
+}
+
+// expect skips messages until one matches.
+func (c *testClient) expect(t *testing.T, what string, match func(Message) bool) Message {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case m, ok := <-c.messages:
+			if !ok {
+				t.Fatalf("connection closed waiting for %s", what)
+			}
+			if match(m) {
+				return m
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func (c *testClient) expectType(t *testing.T, typ string) Message {
+	t.Helper()
+	return c.expect(t, typ, func(m Message) bool { return m.Type == typ })
+}
+
+func (c *testClient) expectEvent(t *testing.T, match func(blackjack.Event) bool) blackjack.Event {
+	t.Helper()
+	m := c.expect(t, "event", func(m Message) bool {
+		if m.Type != MessageEvent {
+			return false
+		}
+		ev, err := m.DecodeEvent()
+		return err == nil && match(ev)
+	})
+	ev, _ := m.DecodeEvent()
+	return ev
+}
+
+func (c *testClient) expectTurn(t *testing.T, seat int) {
+	t.Helper()
+	c.expect(t, "turn", func(m Message) bool {
+		return m.Type == MessageState && m.State.Phase == blackjack.PhasePlayerTurn && m.State.Turn == seat
+	})
+}
+
+func newServer(t *testing.T, cards string, options ...OptionFunc) *Server {
+	t.Helper()
+	stacked, err := deck.ParseDeck(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe, err := deck.NewShoeFromCards(stacked, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(blackjack.DefaultRules(), append(options, WithEngineOptions(blackjack.WithShoe(shoe)))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestTable(t *testing.T) {
+	// Dealer shows a 9, Alice holds 19 and Bob 16, the dealer draws to 21.
+	s := newServer(t, "9s Ts 8s 7c 9d 8d 5h", WithTurnTimeout(100*time.Millisecond))
+
+	spectator := connect(t, s.Connect())
+	alice := connect(t, s.Connect())
+	bob := connect(t, s.Connect())
+
+	alice.Join("Alice")
+	welcome := alice.expectType(t, MessageWelcome)
+	if *welcome.Seat != 0 || welcome.Token == "" {
+		t.Fatalf("expected Alice at seat 0 with a token, got %+v", welcome)
+	}
+	bob.Join("Bob")
+	if m := bob.expectType(t, MessageWelcome); *m.Seat != 1 {
+		t.Fatalf("expected Bob at seat 1, got %+v", m)
+	}
+
+	spectator.Bet(10)
+	if m := spectator.expectType(t, MessageError); !strings.Contains(m.Error, ErrNotSeated.Error()) {
+		t.Fatalf("expected spectator not to bet, got %+v", m)
+	}
+
+	alice.Bet(10)
+	bob.Bet(10)
+	alice.expectTurn(t, 0)
+	alice.Act(blackjack.Stand)
+
+	// Bob never acts and stands when his time runs out.
+	bob.expectTurn(t, 1)
+	spectator.expectEvent(t, func(ev blackjack.Event) bool {
+		return ev == blackjack.PlayerActed{Seat: 1, Action: blackjack.Stand}
+	})
+	ev := spectator.expectEvent(t, func(ev blackjack.Event) bool {
+		_, ok := ev.(blackjack.RoundSettled)
+		return ok
+	})
+	if settled := ev.(blackjack.RoundSettled); len(settled.Results) != 2 || settled.Results[0].Net != -10 || settled.Results[1].Net != -10 {
+		t.Fatalf("expected both players to lose 10, got %+v", settled)
+	}
+
+	// Alice loses her connection and takes her seat back with the token.
+	alice.Close()
+	again := connect(t, s.Connect())
+	again.Resume("not a token")
+	if m := again.expectType(t, MessageError); !strings.Contains(m.Error, ErrToken.Error()) {
+		t.Fatalf("expected unknown token error, got %+v", m)
+	}
+	again.Resume(welcome.Token)
+	if m := again.expectType(t, MessageWelcome); *m.Seat != 0 {
+		t.Fatalf("expected Alice back at seat 0, got %+v", m)
+	}
+	again.Bet(10)
+	again.expect(t, "bet", func(m Message) bool {
+		return m.Type == MessageState && m.State.Phase == blackjack.PhaseBetting && m.State.Seats[0].Bet == 10
+	})
+
+	// Bob leaves while bets are taken.
+	bob.Leave()
+	spectator.expectEvent(t, func(ev blackjack.Event) bool {
+		return ev == blackjack.PlayerLeft{Seat: 1, Name: "Bob"}
+	})
+}
+
+func TestResumeKeepsDeadline(t *testing.T) {
+	s := newServer(t, "Ts 9s 6s 7c 5h", WithTurnTimeout(200*time.Millisecond))
+	alice := connect(t, s.Connect())
+	alice.Join("Alice")
+	welcome := alice.expectType(t, MessageWelcome)
+	alice.Bet(10)
+	alice.expectTurn(t, 0)
+
+	// Resuming the seat over and over does not put off standing when the time runs out.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(20 * time.Millisecond):
+				alice.Resume(welcome.Token)
+			}
+		}
+	}()
+	alice.expectEvent(t, func(ev blackjack.Event) bool {
+		return ev == blackjack.PlayerActed{Seat: 0, Action: blackjack.Stand}
+	})
+}
+
+func TestTCP(t *testing.T) {
+	s := newServer(t, "9s Ts 7c 9d")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- s.Serve(l) }()
+
+	c, err := Dial(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := connect(t, c)
+	alice.Join("Alice")
+	alice.expectType(t, MessageWelcome)
+
+	s.Close()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	s := newServer(t, "9s Ts 7c 9d")
+	hs := httptest.NewServer(s.WebSocket())
+	defer hs.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(hs.URL, "http"), "", hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if err := websocket.JSON.Send(ws, Request{Type: RequestJoin, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var m Message
+		if err := websocket.JSON.Receive(ws, &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Type == MessageWelcome {
+			break
+		}
+	}
+}
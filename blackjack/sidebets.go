@@ -0,0 +1,189 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// ErrUnknownSideBet is returned when wagering on a side bet the table does not offer.
+var ErrUnknownSideBet = errors.New("blackjack: side bet not offered")
+
+// Names of the side bets built in.
+const (
+	PerfectPairsName       = "Perfect Pairs"
+	TwentyOnePlusThreeName = "21+3"
+	LuckyLadiesName        = "Lucky Ladies"
+)
+
+// Pay is what an outcome of a side bet pays for every unit bet, e.g 25 for 25 to 1.
+type Pay struct {
+	Outcome string `json:"outcome"`
+	Pays    int    `json:"pays"`
+}
+
+// SideBet is a wager placed with the main bet and settled on the first two cards of
+// the player and of the dealer. Evaluate names the outcome of the cards, or returns an
+// empty string when the bet loses. Outcomes missing from the pay table lose as well,
+// so a pay table can leave out outcomes a casino does not pay.
+type SideBet struct {
+	Name     string                                  `json:"name"`
+	Pays     []Pay                                   `json:"pays"`
+	Evaluate func(player, dealer []deck.Card) string `json:"-"`
+}
+
+// Payout returns the winning outcome of the cards and what it pays per unit bet.
+func (b SideBet) Payout(player, dealer []deck.Card) (outcome string, pays int, ok bool) {
+	outcome = b.Evaluate(player, dealer)
+	if outcome == "" {
+		return "", 0, false
+	}
+	i := slices.IndexFunc(b.Pays, func(p Pay) bool { return p.Outcome == outcome })
+	if i < 0 {
+		return "", 0, false
+	}
+	return outcome, b.Pays[i].Pays, true
+}
+
+// SideBetter can be implemented by an AI that wagers on side bets. PlayRound asks it
+// after the main bet for the amount to wager on each side bet offered, 0 to skip one.
+type SideBetter interface {
+	DecideSideBet(bet SideBet) int
+}
+
+// PerfectPairs pays when the player's first two cards are a pair: a perfect pair of
+// the same suit, a colored pair of the same color or a mixed pair.
+func PerfectPairs() SideBet {
+	return SideBet{
+		Name:     PerfectPairsName,
+		Pays:     []Pay{{"perfect pair", 25}, {"colored pair", 12}, {"mixed pair", 6}},
+		Evaluate: perfectPairs,
+	}
+}
+
+func perfectPairs(player, _ []deck.Card) string {
+	a, b := player[0], player[1]
+	switch {
+	case a.Type != b.Type:
+		return ""
+	case a.Suit == b.Suit:
+		return "perfect pair"
+	case red(a) == red(b):
+		return "colored pair"
+	}
+	return "mixed pair"
+}
+
+func red(c deck.Card) bool {
+	return c.Suit == deck.HEART || c.Suit == deck.DIAMOND
+}
+
+// TwentyOnePlusThree pays for a poker hand made of the player's first two cards and
+// the dealer's up card: suited trips, a straight flush, three of a kind, a straight
+// or a flush. Aces play high or low in straights.
+func TwentyOnePlusThree() SideBet {
+	return SideBet{
+		Name: TwentyOnePlusThreeName,
+		Pays: []Pay{
+			{"suited trips", 100},
+			{"straight flush", 40},
+			{"three of a kind", 30},
+			{"straight", 10},
+			{"flush", 5},
+		},
+		Evaluate: twentyOnePlusThree,
+	}
+}
+
+func twentyOnePlusThree(player, dealer []deck.Card) string {
+	cards := []deck.Card{player[0], player[1], dealer[0]}
+	flush := cards[0].Suit == cards[1].Suit && cards[1].Suit == cards[2].Suit
+	trips := cards[0].Type == cards[1].Type && cards[1].Type == cards[2].Type
+
+	ranks := []int{int(cards[0].Type), int(cards[1].Type), int(cards[2].Type)}
+	slices.Sort(ranks)
+	straight := ranks[1] == ranks[0]+1 && ranks[2] == ranks[1]+1 ||
+		// Queen, king and ace.
+		ranks[0] == int(deck.ACE) && ranks[1] == int(deck.QUEEN) && ranks[2] == int(deck.KING)
+
+	switch {
+	case trips && flush:
+		return "suited trips"
+	case straight && flush:
+		return "straight flush"
+	case trips:
+		return "three of a kind"
+	case straight:
+		return "straight"
+	case flush:
+		return "flush"
+	}
+	return ""
+}
+
+// LuckyLadies pays when the player's first two cards total 20: most for a pair of
+// queens of hearts against a dealer blackjack, then for the queens of hearts alone,
+// then for a matched 20 of the same rank and suit, a suited 20 and any 20.
+func LuckyLadies() SideBet {
+	return SideBet{
+		Name: LuckyLadiesName,
+		Pays: []Pay{
+			{"queens of hearts with dealer blackjack", 1000},
+			{"queens of hearts", 200},
+			{"matched 20", 25},
+			{"suited 20", 10},
+			{"any 20", 4},
+		},
+		Evaluate: luckyLadies,
+	}
+}
+
+func luckyLadies(player, dealer []deck.Card) string {
+	a, b := player[0], player[1]
+	hand := Hand{Hand: player}
+	if hand.Value() != 20 {
+		return ""
+	}
+	queenOfHearts := deck.Card{Suit: deck.HEART, Type: deck.QUEEN}
+	dealerHand := Hand{Hand: dealer}
+	switch {
+	case a == queenOfHearts && b == queenOfHearts && dealerHand.IsBlackjack():
+		return "queens of hearts with dealer blackjack"
+	case a == queenOfHearts && b == queenOfHearts:
+		return "queens of hearts"
+	case a == b:
+		return "matched 20"
+	case a.Suit == b.Suit:
+		return "suited 20"
+	}
+	return "any 20"
+}
+
+// builtinSideBets are the side bets a hand history can be replayed with.
+var builtinSideBets = map[string]func(player, dealer []deck.Card) string{
+	PerfectPairsName:       perfectPairs,
+	TwentyOnePlusThreeName: twentyOnePlusThree,
+	LuckyLadiesName:        luckyLadies,
+}
+
+// builtin reports whether the side bet is evaluated by the built in side bet of its name.
+func (b SideBet) builtin() bool {
+	evaluate, ok := builtinSideBets[b.Name]
+	return ok && reflect.ValueOf(evaluate).Pointer() == reflect.ValueOf(b.Evaluate).Pointer()
+}
+
+// checkSideBets validates the side bets offered at a table.
+func checkSideBets(bets []SideBet) error {
+	for i, b := range bets {
+		if b.Evaluate == nil {
+			return fmt.Errorf("blackjack: side bet %q has no Evaluate function", b.Name)
+		}
+		if slices.ContainsFunc(bets[:i], func(o SideBet) bool { return o.Name == b.Name }) {
+			return fmt.Errorf("blackjack: side bet %q offered twice", b.Name)
+		}
+	}
+	return nil
+}
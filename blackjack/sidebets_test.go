@@ -0,0 +1,216 @@
+package blackjack
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func TestSideBetOutcomes(t *testing.T) {
+	tests := []struct {
+		bet            SideBet
+		player, dealer string
+		outcome        string
+	}{
+		{PerfectPairs(), "Kh Kh", "2s 3s", "perfect pair"},
+		{PerfectPairs(), "Kh Kd", "2s 3s", "colored pair"},
+		{PerfectPairs(), "Kh Ks", "2s 3s", "mixed pair"},
+		{PerfectPairs(), "Kh Qh", "2s 3s", ""},
+		{TwentyOnePlusThree(), "7h 7h", "7h 3s", "suited trips"},
+		{TwentyOnePlusThree(), "Qd Kd", "Ad 3s", "straight flush"},
+		{TwentyOnePlusThree(), "7h 7c", "7s 3s", "three of a kind"},
+		{TwentyOnePlusThree(), "Ah 2c", "3s 3s", "straight"},
+		{TwentyOnePlusThree(), "Kh Ac", "2s 3s", ""},
+		{TwentyOnePlusThree(), "2h 9h", "Kh 3s", "flush"},
+		{LuckyLadies(), "Qh Qh", "As Ks", "queens of hearts with dealer blackjack"},
+		{LuckyLadies(), "Qh Qh", "9s Ks", "queens of hearts"},
+		{LuckyLadies(), "Js Js", "9s Ks", "matched 20"},
+		{LuckyLadies(), "Js 10s", "9s Ks", "suited 20"},
+		{LuckyLadies(), "Ad 9s", "9s Ks", "any 20"},
+		{LuckyLadies(), "Ad 8s", "9s Ks", ""},
+	}
+	for _, test := range tests {
+		player, err := deck.ParseDeck(test.player)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealer, err := deck.ParseDeck(test.dealer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := test.bet.Evaluate(player, dealer); got != test.outcome {
+			t.Errorf("%s on %s against %s: expected %q, got %q", test.bet.Name, test.player, test.dealer, test.outcome, got)
+		}
+	}
+}
+
+func TestEngineSideBets(t *testing.T) {
+	// Dealer up card, player, hole card, player: a perfect pair that stands on 20.
+	cards, err := deck.ParseDeck("9c Kh 8c Kh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe, err := deck.NewShoeFromCards(cards, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pays := PerfectPairs()
+	pays.Pays = []Pay{{"perfect pair", 30}}
+	e, err := NewEngine(DefaultRules(), WithShoe(shoe), WithSideBets(pays, TwentyOnePlusThree()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settled RoundSettled
+	e.AddListener(func(ev Event) {
+		if ev, ok := ev.(RoundSettled); ok {
+			settled = ev
+		}
+	})
+	seat, _ := e.AddPlayer("Player", nil, WithBalance(100))
+
+	e.NewRound()
+	if err := e.PlaceSideBet(seat, PerfectPairsName, 5); !errors.Is(err, ErrNoBets) {
+		t.Fatalf("expected a side bet without a main bet to fail, got %v", err)
+	}
+	e.PlaceBet(seat, 10)
+	if err := e.PlaceSideBet(seat, LuckyLadiesName, 5); !errors.Is(err, ErrUnknownSideBet) {
+		t.Fatalf("expected an unknown side bet to fail, got %v", err)
+	}
+	if err := e.PlaceSideBet(seat, PerfectPairsName, 100); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("expected a side bet over the balance to fail, got %v", err)
+	}
+	for name, amount := range map[string]int{PerfectPairsName: 5, TwentyOnePlusThreeName: 2} {
+		if err := e.PlaceSideBet(seat, name, amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if st, _ := e.State().Seat(seat); st.SideBets[PerfectPairsName] != 5 {
+		t.Fatalf("expected the side bets in the state, got %+v", st)
+	}
+	if err := e.Deal(); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Act(seat, Stand); err != nil {
+		t.Fatal(err)
+	}
+
+	r := settled.Results[0]
+	want := []SideBetResult{
+		{Name: PerfectPairsName, Bet: 5, Outcome: "perfect pair", Amount: 150},
+		{Name: TwentyOnePlusThreeName, Bet: 2, Amount: -2},
+	}
+	if len(r.SideBets) != 2 || r.SideBets[0] != want[0] || r.SideBets[1] != want[1] {
+		t.Fatalf("expected side bets %+v, got %+v", want, r.SideBets)
+	}
+	// The 20 beats the dealer's 17.
+	if r.Net != 10+150-2 {
+		t.Fatalf("expected a net of 158, got %v", r.Net)
+	}
+	if st, _ := e.State().Seat(seat); *st.Balance != 258 {
+		t.Fatalf("expected a balance of 258, got %v", *st.Balance)
+	}
+}
+
+func TestSimulateSideBets(t *testing.T) {
+	r, err := Simulate(DefaultRules(), mimicDealer, 200000, WithSeed(3), WithSimulatedSideBets(PerfectPairs(), TwentyOnePlusThree(), LuckyLadies()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := Simulate(DefaultRules(), mimicDealer, 200000, WithSeed(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Net != plain.Net {
+		t.Fatalf("expected side bets left out of the main game, got %v and %v", r.Net, plain.Net)
+	}
+
+	// House edges in a six deck shoe are about 4% for Perfect Pairs, 3% for 21+3
+	// and 17% for Lucky Ladies, which wins about 1 bet in 10.
+	edges := map[string][2]float64{
+		PerfectPairsName:       {0, 0.1},
+		TwentyOnePlusThreeName: {0, 0.1},
+		LuckyLadiesName:        {0.05, 0.35},
+	}
+	if len(r.SideBets) != 3 {
+		t.Fatalf("expected a report for every side bet, got\n%s", r)
+	}
+	for _, sb := range r.SideBets {
+		if sb.Bets != r.Rounds || sb.HouseEdge < edges[sb.Name][0] || sb.HouseEdge > edges[sb.Name][1] {
+			t.Errorf("unexpected side bet report %+v", sb)
+		}
+	}
+
+	// An AI deciding its side bets wagers what it wants.
+	newAI := func() AI { return sideBettingAI{scriptedAI{hitBelow: 17, bet: 10}} }
+	r, err = Simulate(DefaultRules(), newAI, 1000, WithSeed(3), WithSimulatedSideBets(PerfectPairs(), LuckyLadies()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sb := range r.SideBets {
+		if sb.Bets != r.Rounds || sb.Wagered != float64(sb.Bets*len(sb.Name)) {
+			t.Errorf("expected %d bets of %d on %s, got %+v", r.Rounds, len(sb.Name), sb.Name, sb)
+		}
+	}
+}
+
+type sideBettingAI struct {
+	scriptedAI
+}
+
+func (sideBettingAI) DecideSideBet(b SideBet) int {
+	return len(b.Name)
+}
+
+func TestReplaySideBets(t *testing.T) {
+	var buf bytes.Buffer
+	e, err := NewEngine(DefaultRules(), WithShoeSeed(5), WithSideBets(PerfectPairs(), TwentyOnePlusThree(), LuckyLadies()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRecorder(e, &buf)
+	e.AddPlayer("Side", sideBettingAI{scriptedAI{hitBelow: 17, bet: 10}})
+	for range 50 {
+		if err := e.PlayRound(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+
+	history, err := ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range history {
+		if results := h.Events[len(h.Events)-1].(RoundSettled).Results; len(results[0].SideBets) != 3 {
+			t.Fatalf("expected every side bet settled, got %+v", results)
+		}
+		if err := Replay(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A side bet named like a built in one may have other rules, even when nobody wagers on it.
+	custom := TwentyOnePlusThree()
+	custom.Evaluate = func(player, dealer []deck.Card) string { return "flush" }
+	buf.Reset()
+	e, err = NewEngine(DefaultRules(), WithShoeSeed(5), WithSideBets(custom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = NewRecorder(e, &buf)
+	e.AddPlayer("Main", scriptedAI{hitBelow: 17, bet: 10})
+	if err := e.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+	history, err = ReadHistory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || Replay(history[0]) == nil {
+		t.Fatalf("expected the round offering a custom side bet not to replay, got %+v", history)
+	}
+}
@@ -0,0 +1,423 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Number of rounds played on the same engine and shoe. Rounds are split into fixed
+// chunks so the report for a seed does not depend on the number of workers.
+const simulationChunk = 10000
+
+// Type SimulationOptions is used to configure Simulate.
+//
+// Workers: number of goroutines used. Defaults to the number of CPUs. Set with WithWorkers function
+//
+// Seed: seed used to shuffle the shoes, the same seed always gives the same report. When not
+// set a random seed is used. Set with WithSeed function
+//
+// Bankroll: bankroll used to compute the risk of ruin. Defaults to 100 times the average
+// bet. Set with WithBankroll function
+//
+// SideBets: side bets offered every round, reported apart from the main game. An AI
+// implementing SideBetter decides its wagers, other AIs wager one unit on each side
+// bet every round they bet. Set with WithSimulatedSideBets function
+type SimulationOptions struct {
+	Workers  int
+	Seed     *int64
+	Bankroll float64
+	SideBets []SideBet
+}
+
+// type SimulationOptionFunc acts a wrapper for functional
+// options used for configuration in Simulate
+type SimulationOptionFunc func(*SimulationOptions)
+
+// Option that sets the number of goroutines used.
+func WithWorkers(n int) SimulationOptionFunc {
+	return func(o *SimulationOptions) {
+		o.Workers = n
+	}
+}
+
+// Option that makes the simulation deterministic.
+func WithSeed(seed int64) SimulationOptionFunc {
+	return func(o *SimulationOptions) {
+		o.Seed = &seed
+	}
+}
+
+// Option that sets the bankroll used to compute the risk of ruin.
+func WithBankroll(bankroll float64) SimulationOptionFunc {
+	return func(o *SimulationOptions) {
+		o.Bankroll = bankroll
+	}
+}
+
+// Option that offers the side bets every round and reports their house edge.
+func WithSimulatedSideBets(bets ...SideBet) SimulationOptionFunc {
+	return func(o *SimulationOptions) {
+		o.SideBets = append(o.SideBets, bets...)
+	}
+}
+
+// Report holds the statistics of a simulation. Returns are measured per round
+// relative to the initial bet, so doubles, splits and insurance are included. The
+// house edge is the net lost over the total bet and its confidence interval allows
+// for bets that vary from round to round. Side bets are left out of the main game
+// and reported on their own.
+type Report struct {
+	Rules              Rules           `json:"-"`
+	Rounds             int             `json:"rounds"`
+	TotalBet           float64         `json:"totalBet"`
+	Net                float64         `json:"net"`
+	HouseEdge          float64         `json:"houseEdge"`
+	Variance           float64         `json:"variance"`
+	StdDev             float64         `json:"stdDev"`
+	ConfidenceInterval [2]float64      `json:"confidenceInterval95"`
+	WinRate            float64         `json:"winRate"`
+	LossRate           float64         `json:"lossRate"`
+	PushRate           float64         `json:"pushRate"`
+	PlayerBustRate     float64         `json:"playerBustRate"`
+	DealerBust         []UpCardReport  `json:"dealerBust"`
+	Bankroll           float64         `json:"bankroll"`
+	RiskOfRuin         float64         `json:"riskOfRuin"`
+	SideBets           []SideBetReport `json:"sideBets,omitempty"`
+}
+
+// SideBetReport holds the results of a side bet. HitRate is how often it wins and
+// Outcomes counts the wins by outcome.
+type SideBetReport struct {
+	Name      string         `json:"name"`
+	Bets      int            `json:"bets"`
+	Wagered   float64        `json:"wagered"`
+	Net       float64        `json:"net"`
+	HouseEdge float64        `json:"houseEdge"`
+	HitRate   float64        `json:"hitRate"`
+	Outcomes  map[string]int `json:"outcomes"`
+}
+
+// UpCardReport holds how often the dealer busts when showing a card.
+type UpCardReport struct {
+	UpCard   string  `json:"upCard"`
+	Hands    int     `json:"hands"`
+	BustRate float64 `json:"bustRate"`
+}
+
+// JSON returns the report as indented JSON.
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rules:            %s\n", r.Rules)
+	fmt.Fprintf(&b, "Rounds:           %d\n", r.Rounds)
+	fmt.Fprintf(&b, "Total bet:        %.0f\n", r.TotalBet)
+	fmt.Fprintf(&b, "Net:              %+.1f\n", r.Net)
+	fmt.Fprintf(&b, "House edge:       %.3f%% (95%% CI %.3f%% to %.3f%%)\n",
+		100*r.HouseEdge, 100*r.ConfidenceInterval[0], 100*r.ConfidenceInterval[1])
+	fmt.Fprintf(&b, "Variance:         %.4f\n", r.Variance)
+	fmt.Fprintf(&b, "Std deviation:    %.4f\n", r.StdDev)
+	fmt.Fprintf(&b, "Win/loss/push:    %.2f%% / %.2f%% / %.2f%%\n", 100*r.WinRate, 100*r.LossRate, 100*r.PushRate)
+	fmt.Fprintf(&b, "Player busts:     %.2f%% of hands\n", 100*r.PlayerBustRate)
+	fmt.Fprintf(&b, "Risk of ruin:     %.2f%% with a bankroll of %.0f\n", 100*r.RiskOfRuin, r.Bankroll)
+	fmt.Fprintf(&b, "Dealer bust rate by up card:\n")
+	for _, u := range r.DealerBust {
+		fmt.Fprintf(&b, "  %-3s %6.2f%% of %d hands\n", u.UpCard, 100*u.BustRate, u.Hands)
+	}
+	if len(r.SideBets) > 0 {
+		fmt.Fprintf(&b, "Side bets:\n")
+	}
+	for _, s := range r.SideBets {
+		fmt.Fprintf(&b, "  %-14s house edge %.3f%%, wins %.2f%% of %d bets\n", s.Name, 100*s.HouseEdge, 100*s.HitRate, s.Bets)
+	}
+	return b.String()
+}
+
+// upCards orders dealer up cards by their value in blackjack.
+var upCards = [...]string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "A"}
+
+// simulationStats accumulates the results of the rounds of one chunk.
+type simulationStats struct {
+	rounds       int
+	totalBet     float64
+	net          float64
+	returns      float64 // sum of net/bet over rounds
+	returnsSq    float64
+	netSq        float64
+	betSq        float64
+	netBet       float64 // sum of net*bet over rounds
+	wins, losses int
+	hands, busts int
+	upCardHands  [len(upCards)]int
+	upCardBusts  [len(upCards)]int
+	upCard       int
+	dealerBusted bool
+	dealerPlayed bool
+	roundInitial int
+	sideBets     map[string]*sideBetStats
+}
+
+type sideBetStats struct {
+	bets, wins int
+	wagered    float64
+	net        float64
+	outcomes   map[string]int
+}
+
+func (s *simulationStats) listen(ev Event) {
+	switch ev := ev.(type) {
+	case BetPlaced:
+		s.roundInitial += ev.Amount
+	case CardDealt:
+		// The first card of the round dealt to the dealer is the up card.
+		if ev.Seat == DealerSeat && s.upCard < 0 {
+			s.upCard = upCardIndex(ev.Card)
+		}
+	case PlayerBusts:
+		s.busts++
+	case DealerBusts:
+		s.dealerPlayed, s.dealerBusted = true, true
+	case DealerStands, DealerBlackjack:
+		s.dealerPlayed = true
+	case RoundSettled:
+		var net float64
+		for _, r := range ev.Results {
+			net += r.Net
+			s.hands += len(r.Hands)
+			for _, sb := range r.SideBets {
+				net -= sb.Amount
+				s.addSideBet(sb)
+			}
+		}
+		bet := float64(s.roundInitial)
+		s.rounds++
+		s.totalBet += bet
+		s.net += net
+		s.netSq += net * net
+		s.betSq += bet * bet
+		s.netBet += net * bet
+		s.returns += net / bet
+		s.returnsSq += (net / bet) * (net / bet)
+		switch {
+		case net > 0:
+			s.wins++
+		case net < 0:
+			s.losses++
+		}
+		if s.dealerPlayed && s.upCard >= 0 {
+			s.upCardHands[s.upCard]++
+			if s.dealerBusted {
+				s.upCardBusts[s.upCard]++
+			}
+		}
+		s.upCard, s.dealerPlayed, s.dealerBusted, s.roundInitial = -1, false, false, 0
+	}
+}
+
+// sideBet returns the statistics of a side bet.
+func (s *simulationStats) sideBet(name string) *sideBetStats {
+	if s.sideBets == nil {
+		s.sideBets = map[string]*sideBetStats{}
+	}
+	sb, ok := s.sideBets[name]
+	if !ok {
+		sb = &sideBetStats{outcomes: map[string]int{}}
+		s.sideBets[name] = sb
+	}
+	return sb
+}
+
+func (s *simulationStats) addSideBet(r SideBetResult) {
+	sb := s.sideBet(r.Name)
+	sb.bets++
+	sb.wagered += float64(r.Bet)
+	sb.net += r.Amount
+	if r.Outcome != "" {
+		sb.wins++
+		sb.outcomes[r.Outcome]++
+	}
+}
+
+func (s *simulationStats) add(o *simulationStats) {
+	s.rounds += o.rounds
+	s.totalBet += o.totalBet
+	s.net += o.net
+	s.returns += o.returns
+	s.returnsSq += o.returnsSq
+	s.netSq += o.netSq
+	s.betSq += o.betSq
+	s.netBet += o.netBet
+	s.wins += o.wins
+	s.losses += o.losses
+	s.hands += o.hands
+	s.busts += o.busts
+	for i := range s.upCardHands {
+		s.upCardHands[i] += o.upCardHands[i]
+		s.upCardBusts[i] += o.upCardBusts[i]
+	}
+	for name, sb := range o.sideBets {
+		total := s.sideBet(name)
+		total.bets += sb.bets
+		total.wins += sb.wins
+		total.wagered += sb.wagered
+		total.net += sb.net
+		for outcome, n := range sb.outcomes {
+			total.outcomes[outcome] += n
+		}
+	}
+}
+
+func upCardIndex(c deck.Card) int {
+	if c.Type == deck.ACE {
+		return len(upCards) - 1
+	}
+	return cardValue(c) - 2
+}
+
+// Simulate plays the given number of rounds in parallel without any output and
+// reports the statistics. newAI is called for every engine so that an AI keeping
+// state, such as a card counter, gets its own instance. Every engine deals from its
+// own shoe with a single player seated.
+func Simulate(rules Rules, newAI func() AI, rounds int, options ...SimulationOptionFunc) (Report, error) {
+	o := SimulationOptions{Workers: runtime.NumCPU()}
+	for _, option := range options {
+		option(&o)
+	}
+	if rounds < 1 {
+		return Report{}, fmt.Errorf("blackjack: rounds must be positive, got %d", rounds)
+	}
+	if err := rules.Validate(); err != nil {
+		return Report{}, err
+	}
+	seed := rand.Int63()
+	if o.Seed != nil {
+		seed = *o.Seed
+	}
+
+	chunks := (rounds + simulationChunk - 1) / simulationChunk
+	stats := make([]simulationStats, chunks)
+	errs := make([]error, chunks)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(o.Workers, 1), chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				n := min(simulationChunk, rounds-chunk*simulationChunk)
+				errs[chunk] = simulateChunk(rules, newAI(), o.SideBets, n, seed+int64(chunk)*0x9e3779b9, &stats[chunk])
+			}
+		}()
+	}
+	for chunk := 0; chunk < chunks; chunk++ {
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return Report{}, err
+	}
+
+	// Chunks are added in order so that floating point sums are reproducible.
+	var total simulationStats
+	for i := range stats {
+		total.add(&stats[i])
+	}
+	return total.report(rules, o.Bankroll, o.SideBets), nil
+}
+
+func simulateChunk(rules Rules, ai AI, sideBets []SideBet, rounds int, seed int64, stats *simulationStats) error {
+	stats.upCard = -1
+	e, err := NewEngine(rules, WithShoeSeed(seed), WithListener(stats.listen), WithSideBets(sideBets...))
+	if err != nil {
+		return err
+	}
+	e.sideBet = 1
+	if _, err := e.AddPlayer("AI", ai); err != nil {
+		return err
+	}
+
+	for i := 0; i < rounds; i++ {
+		// ErrNoBets only means the AI sat this round out, the next
+		// call to PlayRound asks it for a bet again.
+		if err := e.PlayRound(); err != nil && !errors.Is(err, ErrNoBets) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *simulationStats) report(rules Rules, bankroll float64, sideBets []SideBet) Report {
+	r := Report{Rules: rules, Rounds: s.rounds, TotalBet: s.totalBet, Net: s.net}
+	if s.rounds == 0 {
+		return r
+	}
+
+	n := float64(s.rounds)
+	mean := s.returns / n
+	r.HouseEdge = -s.net / s.totalBet
+	r.Variance = s.returnsSq/n - mean*mean
+	r.StdDev = math.Sqrt(r.Variance)
+	// The house edge is the ratio of the sums of net and bet, its variance comes from
+	// the delta method so that the interval holds with varying bets too.
+	edge := s.net / s.totalBet
+	meanBet := s.totalBet / n
+	residuals := s.netSq - 2*edge*s.netBet + edge*edge*s.betSq
+	margin := 1.96 * math.Sqrt(max(residuals, 0)/n) / (meanBet * math.Sqrt(n))
+	r.ConfidenceInterval = [2]float64{r.HouseEdge - margin, r.HouseEdge + margin}
+	r.WinRate = float64(s.wins) / n
+	r.LossRate = float64(s.losses) / n
+	r.PushRate = 1 - r.WinRate - r.LossRate
+	if s.hands > 0 {
+		r.PlayerBustRate = float64(s.busts) / float64(s.hands)
+	}
+
+	for i, name := range upCards {
+		u := UpCardReport{UpCard: name, Hands: s.upCardHands[i]}
+		if u.Hands > 0 {
+			u.BustRate = float64(s.upCardBusts[i]) / float64(u.Hands)
+		}
+		r.DealerBust = append(r.DealerBust, u)
+	}
+
+	// Risk of ruin for a player betting the average bet every round, using the
+	// diffusion approximation exp(-2 * mean * bankroll / variance) in money units.
+	r.Bankroll = bankroll
+	if r.Bankroll <= 0 {
+		r.Bankroll = 100 * s.totalBet / n
+	}
+	meanNet := s.net / n
+	varianceNet := s.netSq/n - meanNet*meanNet
+	switch {
+	case meanNet <= 0:
+		r.RiskOfRuin = 1
+	case varianceNet == 0:
+		r.RiskOfRuin = 0
+	default:
+		r.RiskOfRuin = math.Exp(-2 * meanNet * r.Bankroll / varianceNet)
+	}
+
+	for _, b := range sideBets {
+		sb := s.sideBet(b.Name)
+		report := SideBetReport{Name: b.Name, Bets: sb.bets, Wagered: sb.wagered, Net: sb.net, Outcomes: sb.outcomes}
+		if sb.bets > 0 {
+			report.HouseEdge = -sb.net / sb.wagered
+			report.HitRate = float64(sb.wins) / float64(sb.bets)
+		}
+		r.SideBets = append(r.SideBets, report)
+	}
+
+	return r
+}
@@ -0,0 +1,95 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func mimicDealer() AI {
+	return scriptedAI{hitBelow: 17, bet: 2}
+}
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	a, err := Simulate(DefaultRules(), mimicDealer, 30000, WithSeed(1), WithWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Simulate(DefaultRules(), mimicDealer, 30000, WithSeed(1), WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("expected the same report for the same seed, got\n%s\nand\n%s", a, b)
+	}
+	if a.Rounds != 30000 {
+		t.Fatalf("expected 30000 rounds, got %d", a.Rounds)
+	}
+}
+
+// alternatingAI mimics the dealer betting 1 and 100 in turn.
+type alternatingAI struct {
+	scriptedAI
+	rounds int
+}
+
+func (ai *alternatingAI) DecideBet() int {
+	ai.rounds++
+	if ai.rounds%2 == 0 {
+		return 100
+	}
+	return 1
+}
+
+func TestSimulateVaryingBets(t *testing.T) {
+	r, err := Simulate(DefaultRules(), func() AI { return &alternatingAI{scriptedAI: scriptedAI{hitBelow: 17}} }, 100000, WithSeed(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	low, high := r.ConfidenceInterval[0], r.ConfidenceInterval[1]
+	if math.Abs((low+high)/2-r.HouseEdge) > 1e-9 {
+		t.Fatalf("expected the confidence interval centred on the house edge, got\n%s", r)
+	}
+	// Mostly the large bets count, so the interval is wider than with flat bets.
+	if flat := 1.96 * r.StdDev / math.Sqrt(float64(r.Rounds)); (high-low)/2 < 1.2*flat {
+		t.Fatalf("expected a wider interval than %v for varying bets, got\n%s", flat, r)
+	}
+}
+
+func TestSimulateReport(t *testing.T) {
+	r, err := Simulate(DefaultRules(), mimicDealer, 200000, WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mimicking the dealer gives the house an edge of about 5.5%.
+	if r.HouseEdge < 0.03 || r.HouseEdge > 0.08 {
+		t.Fatalf("expected house edge around 5.5%%, got\n%s", r)
+	}
+	if r.ConfidenceInterval[0] > r.HouseEdge || r.ConfidenceInterval[1] < r.HouseEdge {
+		t.Fatalf("expected house edge inside the confidence interval, got\n%s", r)
+	}
+	if sum := r.WinRate + r.LossRate + r.PushRate; sum < 0.999 || sum > 1.001 {
+		t.Fatalf("expected rates to add up to 1, got %v", sum)
+	}
+	// The dealer busts most often showing a 5 or 6 and least often showing an ace.
+	six, ace := r.DealerBust[4], r.DealerBust[9]
+	if six.BustRate < 0.35 || six.BustRate > 0.5 || ace.BustRate > six.BustRate {
+		t.Fatalf("unexpected dealer bust rates, got\n%s", r)
+	}
+	if r.RiskOfRuin != 1 {
+		t.Fatalf("expected certain ruin with a negative edge, got %v", r.RiskOfRuin)
+	}
+
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["houseEdge"] == nil {
+		t.Fatalf("expected house edge in JSON report, got %s", data)
+	}
+}
@@ -0,0 +1,119 @@
+package blackjack
+
+import "github.com/Junior-Green/gophercises/deck"
+
+// State is a snapshot of the table for user interfaces. The dealer's hole
+// card is left out until it is revealed.
+type State struct {
+	Phase        Phase       `json:"phase"`
+	Round        int         `json:"round"`
+	Dealer       []deck.Card `json:"dealer"`
+	DealerHidden bool        `json:"dealerHidden"`
+	DealerValue  int         `json:"dealerValue"`
+	Seats        []SeatState `json:"seats"`
+	Turn         int         `json:"turn"`
+	Hand         int         `json:"hand"`
+	Legal        []Action    `json:"legal"`
+}
+
+// SeatState is the state of an occupied seat.
+type SeatState struct {
+	Seat      int         `json:"seat"`
+	Name      string      `json:"name"`
+	Bet       int         `json:"bet"`
+	Hands     []HandState `json:"hands"`
+	Insured   bool        `json:"insured"`
+	Insurance float64     `json:"insurance"`
+	EvenMoney bool        `json:"evenMoney"`
+	Winnings  float64     `json:"winnings"`
+	Balance   *float64    `json:"balance,omitempty"`
+	// SideBets holds the wagers on side bets by name.
+	SideBets map[string]int `json:"sideBets,omitempty"`
+}
+
+// HandState is the state of one hand held by a seat.
+type HandState struct {
+	Cards       []deck.Card `json:"cards"`
+	Bet         int         `json:"bet"`
+	Value       int         `json:"value"`
+	Soft        bool        `json:"soft"`
+	Split       bool        `json:"split"`
+	Doubled     bool        `json:"doubled"`
+	Surrendered bool        `json:"surrendered"`
+	Done        bool        `json:"done"`
+}
+
+// State returns a snapshot of the table. Turn and Hand are -1 outside of the players' turns.
+func (e *Engine) State() State {
+	st := State{
+		Phase: e.phase,
+		Round: e.round,
+		Turn:  -1,
+		Hand:  -1,
+		Legal: e.LegalActions(),
+	}
+	if seat, hand, ok := e.Turn(); ok {
+		st.Turn, st.Hand = seat, hand
+	}
+
+	if e.revealed {
+		st.Dealer = append(st.Dealer, e.dealer.Hand...)
+		st.DealerValue = e.dealer.Value()
+	} else if len(e.dealer.Hand) > 0 {
+		st.Dealer = []deck.Card{e.UpCard()}
+		st.DealerHidden = true
+		st.DealerValue = (&Hand{Hand: st.Dealer}).Value()
+	}
+
+	for i, s := range e.seats {
+		if s == nil {
+			continue
+		}
+		ss := SeatState{
+			Seat:      i,
+			Name:      s.name,
+			Bet:       s.bet,
+			Insured:   s.insured,
+			Insurance: s.insurance,
+			EvenMoney: s.evenMoney,
+			Winnings:  s.winnings,
+		}
+		if s.limited {
+			balance := s.balance
+			ss.Balance = &balance
+		}
+		for b, amount := range s.sideBets {
+			if amount > 0 {
+				if ss.SideBets == nil {
+					ss.SideBets = map[string]int{}
+				}
+				ss.SideBets[e.sideBets[b].Name] = amount
+			}
+		}
+		for _, h := range s.hands {
+			ss.Hands = append(ss.Hands, HandState{
+				Cards:       append([]deck.Card(nil), h.hand.Hand...),
+				Bet:         h.bet,
+				Value:       h.hand.Value(),
+				Soft:        h.hand.IsSoft(),
+				Split:       h.split,
+				Doubled:     h.doubled,
+				Surrendered: h.surrendered,
+				Done:        h.done,
+			})
+		}
+		st.Seats = append(st.Seats, ss)
+	}
+
+	return st
+}
+
+// Seat returns the state of an occupied seat.
+func (st State) Seat(i int) (SeatState, bool) {
+	for _, s := range st.Seats {
+		if s.Seat == i {
+			return s, true
+		}
+	}
+	return SeatState{}, false
+}
@@ -0,0 +1,470 @@
+package blackjack
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Move is an entry of a strategy chart. Moves that depend on what the rules allow
+// fall back to a second choice, e.g MoveDoubleOrHit hits when doubling is not allowed.
+type Move uint8
+
+const (
+	// MoveNone marks an empty entry. An empty pair entry means the pair is played as a
+	// total, an empty total falls back to standing on hard 17 and soft 19 and hitting below.
+	MoveNone Move = iota
+	MoveHit
+	MoveStand
+	MoveDoubleOrHit
+	MoveDoubleOrStand
+	MoveSplit
+	MoveSplitIfDAS
+	MoveSurrenderOrHit
+	MoveSurrenderOrStand
+	MoveSurrenderOrSplit
+)
+
+// Chart codes as printed on strategy cards.
+var moveCodes = [...]string{"-", "H", "S", "D", "Ds", "P", "Ph", "Rh", "Rs", "Rp"}
+
+func (m Move) String() string {
+	if int(m) < len(moveCodes) {
+		return moveCodes[m]
+	}
+	return "?"
+}
+
+func (m Move) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Move) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*m = MoveNone
+		return nil
+	}
+	for i, code := range moveCodes {
+		if strings.EqualFold(code, s) {
+			*m = Move(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("blackjack: unknown chart move %q", s)
+}
+
+func (m Move) splits() bool {
+	return m == MoveSplit || m == MoveSplitIfDAS || m == MoveSurrenderOrSplit
+}
+
+// Chart is a strategy chart of player hands against the dealer's up card.
+// Hard and Soft are indexed by the hand total, Pairs by the value of the paired
+// card with aces as 11, and every row by the column of the up card (2 to 10, then ace).
+type Chart struct {
+	Hard  [22][10]Move
+	Soft  [22][10]Move
+	Pairs [12][10]Move
+}
+
+// ChartAI plays by a strategy chart, never takes insurance and always bets Bet.
+type ChartAI struct {
+	Chart *Chart
+	Rules Rules
+	Bet   int
+}
+
+// NewBasicStrategy returns an AI playing basic strategy for the rules.
+func NewBasicStrategy(rules Rules, bet int) ChartAI {
+	return ChartAI{Chart: BasicStrategyChart(rules), Rules: rules, Bet: bet}
+}
+
+func (ai ChartAI) DecideBet() int                 { return ai.Bet }
+func (ai ChartAI) DecideInsurance(hand Hand) bool { return false }
+
+func (ai ChartAI) ChooseAction(hand Hand, dealer deck.Card, legal []Action) Action {
+	return ai.Chart.Action(hand, dealer, legal, ai.Rules.DoubleAfterSplit)
+}
+
+func (ai ChartAI) DecideSurrender(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Surrender)
+}
+
+func (ai ChartAI) DecideSplit(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Split)
+}
+
+func (ai ChartAI) DoubleDown(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, DoubleDown)
+}
+
+func (ai ChartAI) DecideHit(hand Hand, dealer deck.Card) bool {
+	return chooses(ai, hand, dealer, Hit)
+}
+
+// chooses answers a decision hook of the AI interface with an ActionChooser, offering
+// it the actions the engine would allow when the hook is asked.
+func chooses(c ActionChooser, hand Hand, dealer deck.Card, action Action) bool {
+	legal := []Action{Hit, Stand, DoubleDown, Split, Surrender}
+	switch action {
+	case Split:
+		legal = legal[:4]
+	case DoubleDown:
+		legal = legal[:3]
+	case Hit:
+		legal = legal[:2]
+	}
+	return c.ChooseAction(hand, dealer, legal) == action
+}
+
+// Move returns the chart entry for the hand against the dealer's up card, looking
+// at the pair entry first when the hand can be split.
+func (c *Chart) Move(hand Hand, dealer deck.Card, canSplit bool) Move {
+	col := upCardIndex(dealer)
+	if canSplit && hand.IsPair() {
+		if m := c.Pairs[pairValue(hand.Hand[0])][col]; m != MoveNone {
+			return m
+		}
+	}
+
+	v := hand.Value()
+	if v > 21 {
+		return MoveStand
+	}
+	m := c.Hard[v][col]
+	if hand.IsSoft() {
+		m = c.Soft[v][col]
+	}
+	if m == MoveNone {
+		m = MoveHit
+		if v >= 19 || (v >= 17 && !hand.IsSoft()) {
+			m = MoveStand
+		}
+	}
+	return m
+}
+
+// Action returns the action the chart plays among the legal actions. das tells
+// whether doubling after a split is allowed, for MoveSplitIfDAS entries.
+func (c *Chart) Action(hand Hand, dealer deck.Card, legal []Action, das bool) Action {
+	canSplit := slices.Contains(legal, Split)
+	m := c.Move(hand, dealer, canSplit)
+	if m == MoveSplitIfDAS && !das {
+		m = c.Move(hand, dealer, false)
+	}
+
+	can := func(a Action) bool { return slices.Contains(legal, a) }
+	switch m {
+	case MoveStand:
+		return Stand
+	case MoveDoubleOrHit:
+		if can(DoubleDown) {
+			return DoubleDown
+		}
+	case MoveDoubleOrStand:
+		if can(DoubleDown) {
+			return DoubleDown
+		}
+		return Stand
+	case MoveSplit, MoveSplitIfDAS:
+		if canSplit {
+			return Split
+		}
+	case MoveSurrenderOrHit:
+		if can(Surrender) {
+			return Surrender
+		}
+	case MoveSurrenderOrStand:
+		if can(Surrender) {
+			return Surrender
+		}
+		return Stand
+	case MoveSurrenderOrSplit:
+		if can(Surrender) {
+			return Surrender
+		}
+		return Split
+	}
+	return Hit
+}
+
+func pairValue(c deck.Card) int {
+	if c.Type == deck.ACE {
+		return 11
+	}
+	return cardValue(c)
+}
+
+// BasicStrategyChart returns the basic strategy chart for a shoe game with the
+// rules, taking the soft 17 rule, doubling rules, doubling after splits and late
+// surrender into account.
+func BasicStrategyChart(rules Rules) *Chart {
+	c := &Chart{}
+	h17 := rules.DealerHitsSoft17
+
+	// fill sets the entries of a row from the up card columns from and to, inclusive.
+	fill := func(row *[10]Move, m Move, from, to int) {
+		for col := from - 2; col <= to-2; col++ {
+			row[col] = m
+		}
+	}
+	const ace = 11
+
+	for v := 4; v <= 21; v++ {
+		fill(&c.Hard[v], MoveHit, 2, ace)
+	}
+	fill(&c.Hard[9], MoveDoubleOrHit, 3, 6)
+	fill(&c.Hard[10], MoveDoubleOrHit, 2, 9)
+	fill(&c.Hard[11], MoveDoubleOrHit, 2, 10)
+	if h17 {
+		fill(&c.Hard[11], MoveDoubleOrHit, ace, ace)
+	}
+	fill(&c.Hard[12], MoveStand, 4, 6)
+	for v := 13; v <= 16; v++ {
+		fill(&c.Hard[v], MoveStand, 2, 6)
+	}
+	for v := 17; v <= 21; v++ {
+		fill(&c.Hard[v], MoveStand, 2, ace)
+	}
+	if rules.Surrender {
+		fill(&c.Hard[15], MoveSurrenderOrHit, 10, 10)
+		fill(&c.Hard[16], MoveSurrenderOrHit, 9, ace)
+		if h17 {
+			fill(&c.Hard[15], MoveSurrenderOrHit, ace, ace)
+			fill(&c.Hard[17], MoveSurrenderOrStand, ace, ace)
+		}
+	}
+
+	for v := 12; v <= 21; v++ {
+		fill(&c.Soft[v], MoveHit, 2, ace)
+	}
+	fill(&c.Soft[13], MoveDoubleOrHit, 5, 6)
+	fill(&c.Soft[14], MoveDoubleOrHit, 5, 6)
+	fill(&c.Soft[15], MoveDoubleOrHit, 4, 6)
+	fill(&c.Soft[16], MoveDoubleOrHit, 4, 6)
+	fill(&c.Soft[17], MoveDoubleOrHit, 3, 6)
+	fill(&c.Soft[18], MoveStand, 2, 8)
+	fill(&c.Soft[18], MoveDoubleOrStand, 3, 6)
+	for v := 19; v <= 21; v++ {
+		fill(&c.Soft[v], MoveStand, 2, ace)
+	}
+	if h17 {
+		fill(&c.Soft[18], MoveDoubleOrStand, 2, 2)
+		fill(&c.Soft[19], MoveDoubleOrStand, 6, 6)
+	}
+
+	split := MoveSplitIfDAS
+	fill(&c.Pairs[2], split, 2, 3)
+	fill(&c.Pairs[2], MoveSplit, 4, 7)
+	fill(&c.Pairs[3], split, 2, 3)
+	fill(&c.Pairs[3], MoveSplit, 4, 7)
+	fill(&c.Pairs[4], split, 5, 6)
+	fill(&c.Pairs[6], split, 2, 2)
+	fill(&c.Pairs[6], MoveSplit, 3, 6)
+	fill(&c.Pairs[7], MoveSplit, 2, 7)
+	fill(&c.Pairs[8], MoveSplit, 2, ace)
+	fill(&c.Pairs[9], MoveSplit, 2, 6)
+	fill(&c.Pairs[9], MoveSplit, 8, 9)
+	fill(&c.Pairs[ace], MoveSplit, 2, ace)
+	if rules.Surrender && h17 {
+		fill(&c.Pairs[8], MoveSurrenderOrSplit, ace, ace)
+	}
+
+	// Charts only list the doubles the rules allow, anything else is a hit.
+	for v := 4; v <= 21; v++ {
+		if rules.canDouble(v) {
+			continue
+		}
+		for _, row := range []*[10]Move{&c.Hard[v], &c.Soft[v]} {
+			for col, m := range row {
+				switch m {
+				case MoveDoubleOrHit:
+					row[col] = MoveHit
+				case MoveDoubleOrStand:
+					row[col] = MoveStand
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+// WriteCSV writes the chart as CSV. The first column labels the rows, H followed by
+// the hard total, S by the soft total and P by the paired card (2 to 10 or A), the
+// other columns are the dealer's up card from 2 to A.
+func (c *Chart) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"hand"}, upCards[:]...))
+
+	write := func(label string, row [10]Move) {
+		record := []string{label}
+		for _, m := range row {
+			record = append(record, m.String())
+		}
+		cw.Write(record)
+	}
+	for v := 4; v <= 21; v++ {
+		write("H"+strconv.Itoa(v), c.Hard[v])
+	}
+	for v := 12; v <= 21; v++ {
+		write("S"+strconv.Itoa(v), c.Soft[v])
+	}
+	for v := 2; v <= 11; v++ {
+		write("P"+pairLabel(v), c.Pairs[v])
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// LoadChartCSV reads a chart in the format written by WriteCSV. Rows that are
+// left out, and entries written as "-" or left empty, are MoveNone.
+func LoadChartCSV(r io.Reader) (*Chart, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("blackjack: empty chart")
+	}
+
+	c := &Chart{}
+	for i, record := range records[1:] {
+		if len(record) != len(upCards)+1 {
+			return nil, fmt.Errorf("blackjack: chart line %d has %d columns, expected %d", i+2, len(record), len(upCards)+1)
+		}
+		row, err := c.row(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("blackjack: chart line %d: %w", i+2, err)
+		}
+		if err := parseRow(row, record[1:], record[0][0] == 'P'); err != nil {
+			return nil, fmt.Errorf("blackjack: chart line %d: %w", i+2, err)
+		}
+	}
+	return c, nil
+}
+
+type chartJSON struct {
+	Hard  map[string][]string `json:"hard"`
+	Soft  map[string][]string `json:"soft"`
+	Pairs map[string][]string `json:"pairs"`
+}
+
+// MarshalJSON encodes the chart as an object with "hard", "soft" and "pairs" tables,
+// each mapping the total or paired card (2 to 10 or A) to the moves against 2 to A.
+func (c *Chart) MarshalJSON() ([]byte, error) {
+	j := chartJSON{Hard: map[string][]string{}, Soft: map[string][]string{}, Pairs: map[string][]string{}}
+	row := func(r [10]Move) []string {
+		moves := make([]string, len(r))
+		for i, m := range r {
+			moves[i] = m.String()
+		}
+		return moves
+	}
+	for v := 4; v <= 21; v++ {
+		j.Hard[strconv.Itoa(v)] = row(c.Hard[v])
+	}
+	for v := 12; v <= 21; v++ {
+		j.Soft[strconv.Itoa(v)] = row(c.Soft[v])
+	}
+	for v := 2; v <= 11; v++ {
+		j.Pairs[pairLabel(v)] = row(c.Pairs[v])
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a chart encoded with MarshalJSON.
+func (c *Chart) UnmarshalJSON(data []byte) error {
+	var j chartJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*c = Chart{}
+	tables := []struct {
+		prefix string
+		rows   map[string][]string
+	}{{"H", j.Hard}, {"S", j.Soft}, {"P", j.Pairs}}
+	for _, t := range tables {
+		for label, moves := range t.rows {
+			row, err := c.row(t.prefix + label)
+			if err != nil {
+				return err
+			}
+			if len(moves) != len(upCards) {
+				return fmt.Errorf("blackjack: chart row %s%s has %d moves, expected %d", t.prefix, label, len(moves), len(upCards))
+			}
+			if err := parseRow(row, moves, t.prefix == "P"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadChartJSON reads a chart in the format written by json.Marshal.
+func LoadChartJSON(r io.Reader) (*Chart, error) {
+	c := &Chart{}
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// row returns the row of the chart for a label such as "H12", "S18" or "PA".
+func (c *Chart) row(label string) (*[10]Move, error) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	if len(label) < 2 {
+		return nil, fmt.Errorf("invalid row %q", label)
+	}
+
+	if label[0] == 'P' {
+		v := 11
+		if label[1:] != "A" {
+			var err error
+			if v, err = strconv.Atoi(label[1:]); err != nil || v < 2 || v > 10 {
+				return nil, fmt.Errorf("invalid pair row %q", label)
+			}
+		}
+		return &c.Pairs[v], nil
+	}
+
+	v, err := strconv.Atoi(label[1:])
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid row %q", label)
+	case label[0] == 'H' && v >= 4 && v <= 21:
+		return &c.Hard[v], nil
+	case label[0] == 'S' && v >= 12 && v <= 21:
+		return &c.Soft[v], nil
+	}
+	return nil, fmt.Errorf("invalid row %q", label)
+}
+
+func parseRow(row *[10]Move, moves []string, pair bool) error {
+	for i, s := range moves {
+		var m Move
+		if err := m.UnmarshalText([]byte(s)); err != nil {
+			return err
+		}
+		if m.splits() && !pair {
+			return fmt.Errorf("blackjack: split move %s in a row that is not a pair", m)
+		}
+		row[i] = m
+	}
+	return nil
+}
+
+func pairLabel(v int) string {
+	if v == 11 {
+		return "A"
+	}
+	return strconv.Itoa(v)
+}
@@ -0,0 +1,152 @@
+package blackjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func hand(types ...deck.Type) Hand {
+	var h Hand
+	for _, t := range types {
+		h.addCard(deck.Card{Suit: deck.SPADE, Type: t})
+	}
+	return h
+}
+
+func TestBasicStrategy(t *testing.T) {
+	all := []Action{Hit, Stand, DoubleDown, Split, Surrender}
+	up := func(t deck.Type) deck.Card { return deck.Card{Suit: deck.HEART, Type: t} }
+
+	s17 := DefaultRules()
+	s17.DealerHitsSoft17 = false
+	noSurrender := DefaultRules()
+	noSurrender.Surrender = false
+	noDAS := DefaultRules()
+	noDAS.DoubleAfterSplit = false
+
+	tests := []struct {
+		name   string
+		rules  Rules
+		hand   Hand
+		dealer deck.Type
+		legal  []Action
+		want   Action
+	}{
+		{"hard 16 against a ten surrenders", DefaultRules(), hand(deck.TEN, deck.SIX), deck.KING, all, Surrender},
+		{"hard 16 without surrender hits", noSurrender, hand(deck.TEN, deck.SIX), deck.KING, all, Hit},
+		{"hard 16 after a hit hits", DefaultRules(), hand(deck.TEN, deck.FOUR, deck.TWO), deck.KING, []Action{Hit, Stand}, Hit},
+		{"hard 12 against a four stands", DefaultRules(), hand(deck.TEN, deck.TWO), deck.FOUR, all, Stand},
+		{"11 against an ace doubles on H17", DefaultRules(), hand(deck.SIX, deck.FIVE), deck.ACE, all, DoubleDown},
+		{"11 against an ace hits on S17", s17, hand(deck.SIX, deck.FIVE), deck.ACE, all, Hit},
+		{"soft 18 against a six doubles", DefaultRules(), hand(deck.ACE, deck.SEVEN), deck.SIX, all, DoubleDown},
+		{"soft 18 against a six stands when doubling is not legal", DefaultRules(), hand(deck.ACE, deck.FIVE, deck.TWO), deck.SIX, []Action{Hit, Stand}, Stand},
+		{"soft 18 against a nine hits", DefaultRules(), hand(deck.ACE, deck.SEVEN), deck.NINE, all, Hit},
+		{"aces split", DefaultRules(), hand(deck.ACE, deck.ACE), deck.TEN, all, Split},
+		{"aces hit when they can not be split", DefaultRules(), hand(deck.ACE, deck.ACE), deck.TEN, []Action{Hit, Stand}, Hit},
+		{"eights against an ace surrender on H17", DefaultRules(), hand(deck.EIGHT, deck.EIGHT), deck.ACE, all, Surrender},
+		{"eights against an ace split on S17", s17, hand(deck.EIGHT, deck.EIGHT), deck.ACE, all, Split},
+		{"tens stand", DefaultRules(), hand(deck.KING, deck.QUEEN), deck.SIX, all, Stand},
+		{"fives double", DefaultRules(), hand(deck.FIVE, deck.FIVE), deck.SIX, all, DoubleDown},
+		{"twos against a three split with DAS", DefaultRules(), hand(deck.TWO, deck.TWO), deck.THREE, all, Split},
+		{"twos against a three hit without DAS", noDAS, hand(deck.TWO, deck.TWO), deck.THREE, all, Hit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ai := NewBasicStrategy(tt.rules, 1)
+			if got := ai.ChooseAction(tt.hand, up(tt.dealer), tt.legal); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBasicStrategyHooks(t *testing.T) {
+	ai := NewBasicStrategy(DefaultRules(), 1)
+	ten := deck.Card{Suit: deck.HEART, Type: deck.TEN}
+
+	if !ai.DecideSurrender(hand(deck.TEN, deck.SIX), ten) {
+		t.Fatal("expected to surrender hard 16 against a ten")
+	}
+	if !ai.DecideHit(hand(deck.TEN, deck.SIX), ten) {
+		t.Fatal("expected to hit hard 16 against a ten")
+	}
+	if ai.DecideSplit(hand(deck.TEN, deck.TEN), ten) {
+		t.Fatal("expected not to split tens")
+	}
+	if !ai.DoubleDown(hand(deck.SIX, deck.FIVE), ten) {
+		t.Fatal("expected to double 11 against a ten")
+	}
+}
+
+func TestChartCSV(t *testing.T) {
+	chart := BasicStrategyChart(DefaultRules())
+
+	var buf bytes.Buffer
+	if err := chart.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "H16,S,S,S,S,S,H,H,Rh,Rh,Rh") {
+		t.Fatalf("expected hard 16 row in CSV, got\n%s", buf.String())
+	}
+
+	loaded, err := LoadChartCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *chart {
+		t.Fatal("expected the same chart after writing and loading CSV")
+	}
+
+	_, err = LoadChartCSV(strings.NewReader("hand,2,3,4,5,6,7,8,9,10,A\nH12,P,H,S,S,S,H,H,H,H,H\n"))
+	if err == nil {
+		t.Fatal("expected an error for a split in a hard total row")
+	}
+}
+
+func TestChartJSON(t *testing.T) {
+	chart := BasicStrategyChart(DefaultRules())
+
+	data, err := json.Marshal(chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadChartJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *chart {
+		t.Fatal("expected the same chart after encoding and decoding JSON")
+	}
+
+	// Rows that are left out fall back to standing on hard 17.
+	partial, err := LoadChartJSON(strings.NewReader(`{"hard": {"16": ["S","S","S","S","S","S","S","S","S","S"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := deck.Card{Suit: deck.HEART, Type: deck.TEN}
+	if m := partial.Move(hand(deck.TEN, deck.SIX), up, false); m != MoveStand {
+		t.Fatalf("expected to stand on 16, got %v", m)
+	}
+	if m := partial.Move(hand(deck.TEN, deck.FIVE), up, false); m != MoveHit {
+		t.Fatalf("expected to hit 15, got %v", m)
+	}
+}
+
+func TestSimulateBasicStrategy(t *testing.T) {
+	rules := DefaultRules()
+	basic := func() AI { return NewBasicStrategy(rules, 2) }
+
+	r, err := Simulate(rules, basic, 400000, WithSeed(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Basic strategy for six decks, H17, DAS and late surrender gives the house about 0.6%.
+	if r.HouseEdge < -0.005 || r.HouseEdge > 0.015 {
+		t.Fatalf("expected house edge around 0.6%%, got\n%s", r)
+	}
+}
@@ -0,0 +1,392 @@
+package blackjack
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// game plays an Engine in the terminal, printing every event and prompting
+// human players for their decisions.
+type game struct {
+	engine       *Engine
+	rounds       int
+	isSimulation bool
+	store        *BankrollStore
+	sessions     map[string]*Session
+}
+
+// SetupSimulation creates a game where the ai plays alone for the given number of rounds.
+func SetupSimulation(rules Rules, ai AI, rounds int) (*game, error) {
+	g, err := newGame(rules)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := g.engine.AddPlayer("AI", ai); err != nil {
+		return nil, err
+	}
+	g.isSimulation = true
+	g.rounds = rounds
+
+	return g, nil
+}
+
+// Setup creates a game played by people at the terminal. When store is not nil every
+// player plays with their bankroll from the store, and the sessions are recorded in it
+// when the game ends. Otherwise players have unlimited money.
+func Setup(rules Rules, store *BankrollStore) (*game, error) {
+	g, err := newGame(rules)
+	if err != nil {
+		return nil, err
+	}
+	g.store = store
+	g.sessions = map[string]*Session{}
+
+	input := getUserInput("Enter number of players: ", validatePositiveInteger)
+
+	numPlayers, err := strconv.Atoi(input)
+	if err != nil {
+		panic("Something unexpected occured")
+	}
+	if err := g.initPlayers(numPlayers); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func newGame(rules Rules) (*game, error) {
+	g := &game{}
+	engine, err := NewEngine(rules, WithListener(g.printEvent))
+	if err != nil {
+		return nil, err
+	}
+	g.engine = engine
+	return g, nil
+}
+
+func (g *game) initPlayers(numPlayers int) error {
+	for i := 0; i < numPlayers; i++ {
+		prompt := fmt.Sprintf("player %d enter your name: ", i+1)
+		name := getUserInput(prompt, validateNonEmptyString)
+
+		var options []PlayerOptionFunc
+		if g.store != nil {
+			balance, err := g.loadBankroll(name)
+			if err != nil {
+				return err
+			}
+			options = append(options, WithBalance(balance))
+			g.sessions[name] = &Session{Start: time.Now()}
+		}
+
+		if _, err := g.engine.AddPlayer(name, NewTerminalPlayer(name), options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadBankroll returns the balance of a player from the store, asking for a buy-in
+// when the player is new or can not make the minimum bet.
+func (g *game) loadBankroll(name string) (float64, error) {
+	b, ok, err := g.store.Get(name)
+	if err != nil {
+		return 0, err
+	}
+	minBet := float64(max(g.engine.Rules().MinBet, 1))
+	if ok && b.Balance >= minBet {
+		fmt.Printf("Welcome back %s, your balance is %v\n", name, b.Balance)
+		return b.Balance, nil
+	}
+
+	prompt := fmt.Sprintf("%s enter your buy-in: ", name)
+	input := getUserInput(prompt, validateBet(int(minBet), 0, nil))
+	buyIn, _ := strconv.Atoi(input)
+	if err := g.store.Set(name, float64(buyIn)); err != nil {
+		return 0, err
+	}
+	return float64(buyIn), nil
+}
+
+// saveSessions records the session of every player in the store.
+func (g *game) saveSessions() {
+	for name, session := range g.sessions {
+		session.End = time.Now()
+		if err := g.store.Record(name, *session); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// Start plays rounds until the simulation is over, the players stop or every player is broke.
+func (g *game) Start() {
+	if g.store != nil {
+		defer g.saveSessions()
+	}
+
+	for i := 0; !g.isSimulation || i < g.rounds; i++ {
+		if err := g.engine.PlayRound(); err != nil {
+			fmt.Println(err)
+			return
+		}
+		g.printPlayerWinnings()
+
+		if len(g.engine.State().Seats) == 0 {
+			fmt.Println("Every player left the table.")
+			return
+		}
+		if !g.isSimulation && !continueGame() {
+			return
+		}
+	}
+
+	if g.isSimulation {
+		fmt.Printf("AI won/lost %v after %d rounds.\n", g.engine.seats[0].winnings, g.rounds)
+	}
+}
+
+func (g *game) printPlayerWinnings() {
+	for _, s := range g.engine.State().Seats {
+		if s.Balance != nil {
+			fmt.Printf("%s winnings: %v, balance: %v\n", s.Name, s.Winnings, *s.Balance)
+			continue
+		}
+		fmt.Printf("%s winnings: %v\n", s.Name, s.Winnings)
+	}
+}
+
+func (g *game) name(seat int) string {
+	if s := g.engine.seats[seat]; s != nil {
+		return s.name
+	}
+	return fmt.Sprintf("Seat %d", seat+1)
+}
+
+func (g *game) printEvent(ev Event) {
+	switch ev := ev.(type) {
+	case RoundStarted:
+		if !g.isSimulation {
+			fmt.Printf("\nRound %d\n", ev.Round)
+			fmt.Println("-------------------------------")
+		}
+	case ShoeShuffled:
+		fmt.Println("Cut card reached, reshuffling the shoe")
+	case PlayerLeft:
+		if ev.Broke {
+			fmt.Printf("%s is broke and leaves the table.\n", ev.Name)
+		}
+	case BetAdjusted:
+		bet := "bet"
+		if ev.SideBet != "" {
+			bet = ev.SideBet + " bet"
+		}
+		if ev.Amount == 0 {
+			fmt.Printf("%s's %s of %d is %s, sitting out.\n", g.name(ev.Seat), bet, ev.Requested, ev.Reason)
+		} else {
+			fmt.Printf("%s's %s of %d is %s, betting %d.\n", g.name(ev.Seat), bet, ev.Requested, ev.Reason, ev.Amount)
+		}
+	case CardDealt:
+		switch {
+		case ev.Seat != DealerSeat:
+			fmt.Printf("%s draws a %s\n", g.name(ev.Seat), ev.Card)
+		case ev.FaceDown:
+			fmt.Println("Dealer draws a card face down")
+		default:
+			fmt.Printf("Dealer draws a %s\n", ev.Card)
+		}
+	case HoleCardRevealed:
+		if !g.isSimulation {
+			fmt.Println("\nDealer's turn")
+			fmt.Println("-------------------------------")
+		}
+		fmt.Printf("Dealer flips a %s\n", ev.Card)
+	case InsuranceDecided:
+		switch {
+		case ev.EvenMoney:
+			fmt.Printf("%s takes even money.\n", g.name(ev.Seat))
+		case ev.Taken:
+			fmt.Printf("%s takes insurance for %v\n", g.name(ev.Seat), ev.Amount)
+		}
+	case PlayerActed:
+		switch ev.Action {
+		case Stand:
+			fmt.Printf("%s stands.\n", g.name(ev.Seat))
+		case DoubleDown:
+			fmt.Println(g.name(ev.Seat), "double downs!")
+		case Split:
+			fmt.Printf("%s splits!\n", g.name(ev.Seat))
+		case Surrender:
+			fmt.Printf("%s surrenders.\n", g.name(ev.Seat))
+		}
+	case PlayerBlackjack:
+		fmt.Printf("%s got a natural blackjack!\n", g.name(ev.Seat))
+	case PlayerBusts:
+		fmt.Printf("%s busts!\n", g.name(ev.Seat))
+	case DealerBlackjack:
+		fmt.Println("\nDealer has Blackjack!")
+	case DealerBusts:
+		fmt.Println("Dealer busts!")
+	case DealerStands:
+		fmt.Printf("\nDealer stands on %d.\n", ev.Value)
+	case RoundSettled:
+		for _, r := range ev.Results {
+			if session, ok := g.sessions[r.Name]; ok {
+				session.Rounds++
+				session.Net += r.Net
+			}
+			if r.Insurance > 0 {
+				fmt.Printf("\n%s wins insurance %v\n", r.Name, r.Insurance)
+			} else if r.Insurance < 0 {
+				fmt.Printf("\n%s loses insurance %v\n", r.Name, -r.Insurance)
+			}
+			for _, h := range r.Hands {
+				if h.Amount > 0 {
+					fmt.Printf("\n%s wins %v\n", r.Name, h.Amount)
+				} else if h.Amount < 0 {
+					fmt.Printf("\n%s loses %v\n", r.Name, -h.Amount)
+				} else {
+					fmt.Printf("\n%s ties with dealer\n", r.Name)
+				}
+			}
+		}
+	}
+}
+
+// terminalPlayer is a person playing at the terminal. Decisions on a hand
+// are made in ChooseAction, so the individual decision hooks are never asked.
+type terminalPlayer struct {
+	name   string
+	seat   int
+	engine *Engine
+}
+
+// NewTerminalPlayer returns an AI that prompts a person at the terminal for every
+// decision, so people can take seats next to AIs, e.g in a tournament.
+func NewTerminalPlayer(name string) AI {
+	return &terminalPlayer{name: name}
+}
+
+func (p *terminalPlayer) ObserveSeat(e *Engine, seat int) {
+	p.engine, p.seat = e, seat
+}
+
+func (p *terminalPlayer) DecideBet() int {
+	rules := p.engine.Rules()
+	prompt := fmt.Sprintf("%s enter bet amount: ", p.name)
+	var balance *float64
+	if s, ok := p.engine.State().Seat(p.seat); ok && s.Balance != nil {
+		balance = s.Balance
+		prompt = fmt.Sprintf("%s enter bet amount (balance %v): ", p.name, *balance)
+	}
+	input := getUserInput(prompt, validateBet(rules.MinBet, rules.MaxBet, balance))
+	bet, _ := strconv.Atoi(input)
+	return bet
+}
+
+func (p *terminalPlayer) DecideInsurance(hand Hand) bool {
+	fmt.Print(deck.RenderCards(hand.Hand, deck.WithColor()))
+	prompt := fmt.Sprintf("%s take insurance? (y/n): ", p.name)
+	if hand.IsBlackjack() {
+		prompt = fmt.Sprintf("%s take even money? (y/n): ", p.name)
+	}
+	input := getUserInput(prompt, validateYesOrNo)
+	return input[0] == 'y' || input[0] == 'Y'
+}
+
+func (p *terminalPlayer) DecideSurrender(Hand, deck.Card) bool { return false }
+func (p *terminalPlayer) DecideSplit(Hand, deck.Card) bool     { return false }
+func (p *terminalPlayer) DoubleDown(Hand, deck.Card) bool      { return false }
+func (p *terminalPlayer) DecideHit(Hand, deck.Card) bool       { return false }
+
+func (p *terminalPlayer) ChooseAction(hand Hand, dealer deck.Card, legal []Action) Action {
+	st := p.engine.State()
+	fmt.Println("\nDealer's Hand:")
+	fmt.Print(deck.RenderCards([]deck.Card{dealer, {}}, deck.WithColor(), deck.WithFaceDown(1)))
+
+	if s, ok := st.Seat(st.Turn); ok && len(s.Hands) > 1 {
+		fmt.Printf("\n%s's Hand %d:\n", p.name, st.Hand+1)
+	} else {
+		fmt.Printf("\n%s's Hand:\n", p.name)
+	}
+	fmt.Print(deck.RenderCards(hand.Hand, deck.WithColor()))
+
+	prompt := ""
+	for i, a := range legal {
+		prompt += fmt.Sprintf("\n[%d] %s", i+1, actionLabels[a])
+	}
+	input := getUserInput(prompt+"\nSelect an option: ", validateChoice(len(legal)))
+	choice, _ := strconv.Atoi(input)
+	return legal[choice-1]
+}
+
+var actionLabels = map[Action]string{
+	Hit:        "HIT",
+	Stand:      "STAND",
+	DoubleDown: "DOUBLE DOWN",
+	Split:      "SPLIT",
+	Surrender:  "SURRENDER",
+}
+
+func continueGame() bool {
+	input := getUserInput("Continue playing? (y/n): ", validateYesOrNo)
+
+	return input[0] == 'y' || input[0] == 'Y'
+}
+
+func validatePositiveInteger(s string) bool {
+	num, err := strconv.Atoi(s)
+	if err != nil {
+		return false
+	}
+	return num > 0
+}
+
+// validateBet accepts a bet within the table limits, 0 for no limit, that the balance covers.
+func validateBet(minBet, maxBet int, balance *float64) func(string) bool {
+	return func(s string) bool {
+		bet, err := strconv.Atoi(s)
+		switch {
+		case err != nil || bet <= 0 || bet < minBet:
+			return false
+		case maxBet > 0 && bet > maxBet:
+			return false
+		}
+		return balance == nil || float64(bet) <= *balance
+	}
+}
+
+func validateYesOrNo(s string) bool {
+	switch s {
+	case "y", "Y", "n", "N", "yes", "no", "Yes", "No":
+		return true
+	}
+	return false
+}
+
+func validateChoice(n int) func(string) bool {
+	return func(s string) bool {
+		num, err := strconv.Atoi(s)
+		return err == nil && num >= 1 && num <= n
+	}
+}
+
+func validateNonEmptyString(s string) bool {
+	return s != ""
+}
+
+func getUserInput(prompt string, validate func(string) bool) string {
+	var input string
+	for {
+		fmt.Printf("\n%s", prompt)
+		if _, err := fmt.Scanf("%s\n", &input); err != nil || !validate(input) {
+			fmt.Print("Invalid input")
+			continue
+		}
+		break
+	}
+
+	return input
+}
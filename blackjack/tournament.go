@@ -0,0 +1,294 @@
+package blackjack
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Entrant is a player of a tournament. The AI makes every decision of the entrant,
+// NewTerminalPlayer seats a person instead so people can play against AIs.
+type Entrant struct {
+	Name string
+	AI   AI
+}
+
+// Type TournamentOptions is used to configure RunTournament.
+//
+// Chips: chips every entrant starts with, carried over from round to round. Defaults
+// to 1000. Set with WithStartingChips function
+//
+// HandsPerRound: number of hands played at every table in a round. Defaults to 20. Set
+// with WithHandsPerRound function
+//
+// Advance: number of players with the most chips advancing from each table. Must be
+// less than half the TableSize so that every round knocks players out. Defaults to 2.
+// Set with WithAdvancing function
+//
+// TableSize: number of seats at a table. Defaults to 7. Set with WithTableSize function
+//
+// Seed: seed used to draw the seats and shuffle the shoes, the same seed and AIs always
+// play the same tournament. When not set a random seed is used. Set with WithTournamentSeed function
+//
+// TableOptions: options of the engine of every table, e.g WithListener to follow the
+// play. The seats and the shoe seed are set by the tournament. Set with WithTableOptions function
+type TournamentOptions struct {
+	Chips         float64
+	HandsPerRound int
+	Advance       int
+	TableSize     int
+	Seed          *int64
+	TableOptions  []EngineOptionFunc
+}
+
+// type TournamentOptionFunc acts a wrapper for functional
+// options used for configuration in RunTournament
+type TournamentOptionFunc func(*TournamentOptions)
+
+// Option that sets the chips every entrant starts with.
+func WithStartingChips(chips float64) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.Chips = chips
+	}
+}
+
+// Option that sets the number of hands played at every table in a round.
+func WithHandsPerRound(n int) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.HandsPerRound = n
+	}
+}
+
+// Option that sets the number of players advancing from each table.
+func WithAdvancing(n int) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.Advance = n
+	}
+}
+
+// Option that sets the number of seats at a table.
+func WithTableSize(n int) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.TableSize = n
+	}
+}
+
+// Option that makes the tournament deterministic.
+func WithTournamentSeed(seed int64) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.Seed = &seed
+	}
+}
+
+// Option used to pass engine options to every table.
+func WithTableOptions(options ...EngineOptionFunc) TournamentOptionFunc {
+	return func(o *TournamentOptions) {
+		o.TableOptions = append(o.TableOptions, options...)
+	}
+}
+
+// Standing is the place of a player with their chips at the end of a round, or of the
+// tournament. Round is the last round the player played.
+type Standing struct {
+	Place int     `json:"place"`
+	Name  string  `json:"name"`
+	Chips float64 `json:"chips"`
+	Round int     `json:"round"`
+}
+
+// TableResult is how a table finished a round, the players with the most chips first.
+type TableResult struct {
+	Round     int        `json:"round"`
+	Table     int        `json:"table"`
+	Final     bool       `json:"final,omitempty"`
+	Standings []Standing `json:"standings"`
+	Advanced  []string   `json:"advanced,omitempty"`
+}
+
+// TournamentResult holds every table played and the final leaderboard. The players
+// of the final table lead it by chips, followed by the others by the round they were
+// knocked out in, the latest first, and then by chips.
+type TournamentResult struct {
+	Tables      []TableResult `json:"tables"`
+	Leaderboard []Standing    `json:"leaderboard"`
+}
+
+func (r TournamentResult) String() string {
+	var b strings.Builder
+	for _, t := range r.Tables {
+		if t.Final {
+			fmt.Fprintf(&b, "Round %d, final table:\n", t.Round)
+		} else {
+			fmt.Fprintf(&b, "Round %d, table %d:\n", t.Round, t.Table)
+		}
+		for _, s := range t.Standings {
+			advanced := ""
+			if slices.Contains(t.Advanced, s.Name) {
+				advanced = " advances"
+			}
+			fmt.Fprintf(&b, "  %d. %-20s %10.2f%s\n", s.Place, s.Name, s.Chips, advanced)
+		}
+	}
+	fmt.Fprintf(&b, "Leaderboard:\n")
+	for _, s := range r.Leaderboard {
+		fmt.Fprintf(&b, "  %d. %-20s %10.2f  round %d\n", s.Place, s.Name, s.Chips, s.Round)
+	}
+	return b.String()
+}
+
+// contender is an entrant still in the tournament.
+type contender struct {
+	Entrant
+	chips float64
+}
+
+// RunTournament plays an elimination tournament. Every round the remaining players are
+// drawn to as few tables as can seat them, with sizes differing by one at most, and
+// play a number of hands with the chips they have. The players with the most chips at
+// each table advance, until everyone left fits at the final table, which decides the
+// leaderboard. A player who goes broke is out right away.
+//
+// As at any table played with PlayRound, bets are cut down to the table maximum and
+// to the chips a player has left, so an AI keeps betting while it can make the
+// minimum bet. Tables are played one after another
+// and the same AI is used at every table of an entrant.
+func RunTournament(rules Rules, entrants []Entrant, options ...TournamentOptionFunc) (TournamentResult, error) {
+	o := TournamentOptions{Chips: 1000, HandsPerRound: 20, Advance: 2, TableSize: 7}
+	for _, option := range options {
+		option(&o)
+	}
+	if err := o.validate(rules, entrants); err != nil {
+		return TournamentResult{}, err
+	}
+	seed := rand.Int63()
+	if o.Seed != nil {
+		seed = *o.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	var left []*contender
+	for _, en := range entrants {
+		left = append(left, &contender{Entrant: en, chips: o.Chips})
+	}
+	minBet := float64(max(rules.MinBet, 1))
+
+	var result TournamentResult
+	var knockedOut [][]Standing
+	for round := 1; len(left) > 0; round++ {
+		rng.Shuffle(len(left), func(i, j int) { left[i], left[j] = left[j], left[i] })
+		final := len(left) <= o.TableSize
+		tables := (len(left) + o.TableSize - 1) / o.TableSize
+
+		var next []*contender
+		var out []Standing
+		for t := 0; t < tables; t++ {
+			var players []*contender
+			for i := t; i < len(left); i += tables {
+				players = append(players, left[i])
+			}
+			if err := playTable(rules, players, o, rng.Int63()); err != nil {
+				return TournamentResult{}, fmt.Errorf("blackjack: round %d, table %d: %w", round, t+1, err)
+			}
+
+			// A stable sort keeps the draw for the seats as the tie breaker.
+			slices.SortStableFunc(players, func(a, b *contender) int { return cmp.Compare(b.chips, a.chips) })
+			table := TableResult{Round: round, Table: t + 1, Final: final}
+			for i, p := range players {
+				s := Standing{Place: i + 1, Name: p.Name, Chips: p.chips, Round: round}
+				table.Standings = append(table.Standings, s)
+				switch {
+				case final:
+					result.Leaderboard = append(result.Leaderboard, s)
+				case i < o.Advance && p.chips >= minBet:
+					table.Advanced = append(table.Advanced, p.Name)
+					next = append(next, p)
+				default:
+					out = append(out, s)
+				}
+			}
+			result.Tables = append(result.Tables, table)
+		}
+
+		slices.SortStableFunc(out, func(a, b Standing) int { return cmp.Compare(b.Chips, a.Chips) })
+		knockedOut = append(knockedOut, out)
+		left = next
+	}
+
+	for _, out := range slices.Backward(knockedOut) {
+		result.Leaderboard = append(result.Leaderboard, out...)
+	}
+	for i := range result.Leaderboard {
+		result.Leaderboard[i].Place = i + 1
+	}
+	return result, nil
+}
+
+func (o TournamentOptions) validate(rules Rules, entrants []Entrant) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case len(entrants) == 0:
+		return errors.New("blackjack: tournament needs at least one entrant")
+	case o.Chips < float64(max(rules.MinBet, 1)):
+		return fmt.Errorf("blackjack: starting chips %v do not cover the minimum bet", o.Chips)
+	case o.HandsPerRound < 1:
+		return fmt.Errorf("blackjack: hands per round must be positive, got %d", o.HandsPerRound)
+	case o.TableSize < 1:
+		return fmt.Errorf("blackjack: table needs at least one seat, got %d", o.TableSize)
+	case o.Advance < 1 || 2*o.Advance >= o.TableSize:
+		return fmt.Errorf("blackjack: %d players can not advance from tables of %d", o.Advance, o.TableSize)
+	}
+	for i, en := range entrants {
+		switch {
+		case en.Name == "":
+			return fmt.Errorf("blackjack: entrant %d has no name", i+1)
+		case en.AI == nil:
+			return fmt.Errorf("blackjack: entrant %q has no AI", en.Name)
+		case slices.ContainsFunc(entrants[:i], func(other Entrant) bool { return other.Name == en.Name }):
+			return fmt.Errorf("blackjack: entrant %q entered twice", en.Name)
+		}
+	}
+	return nil
+}
+
+// playTable plays a round at a new table, leaving the players with their chips.
+func playTable(rules Rules, players []*contender, o TournamentOptions, seed int64) error {
+	bySeat := map[int]*contender{}
+	options := append(slices.Clone(o.TableOptions), WithSeats(o.TableSize), WithShoeSeed(seed), WithListener(func(ev Event) {
+		if settled, ok := ev.(RoundSettled); ok {
+			for _, r := range settled.Results {
+				bySeat[r.Seat].chips += r.Net
+			}
+		}
+	}))
+	e, err := NewEngine(rules, options...)
+	if err != nil {
+		return err
+	}
+	for _, p := range players {
+		seat, err := e.AddPlayer(p.Name, p.AI, WithBalance(p.chips))
+		if err != nil {
+			return err
+		}
+		bySeat[seat] = p
+		// The shoe is new to card counters moving from another table.
+		if co, ok := p.AI.(CardObserver); ok {
+			co.ObserveShuffle(rules.Decks)
+		}
+	}
+
+	for hand := 0; hand < o.HandsPerRound; hand++ {
+		if !slices.ContainsFunc(e.seats, func(s *seat) bool { return s != nil }) {
+			break
+		}
+		// ErrNoBets only means every player sat the hand out.
+		if err := e.PlayRound(); err != nil && !errors.Is(err, ErrNoBets) {
+			return err
+		}
+	}
+	return nil
+}
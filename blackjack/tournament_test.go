@@ -0,0 +1,131 @@
+package blackjack
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// seatedAI records the seats it is told about, like a person who needs to see their balance.
+type seatedAI struct {
+	scriptedAI
+	seats int
+}
+
+func (ai *seatedAI) ObserveSeat(e *Engine, seat int) {
+	if e.seats[seat].ai != ai {
+		panic("observed the wrong seat")
+	}
+	ai.seats++
+}
+
+func tournamentEntrants(n int) ([]Entrant, *seatedAI) {
+	rules := DefaultRules()
+	seated := &seatedAI{scriptedAI: scriptedAI{hitBelow: 17, bet: 25}}
+	entrants := []Entrant{{Name: "Seated", AI: seated}}
+	for i := 1; i < n; i++ {
+		var ai AI = NewBasicStrategy(rules, 10*i)
+		if i%2 == 0 {
+			ai = scriptedAI{hitBelow: 17, double: true, bet: 50 * i}
+		}
+		entrants = append(entrants, Entrant{Name: fmt.Sprintf("Bot %d", i), AI: ai})
+	}
+	return entrants, seated
+}
+
+func TestTournament(t *testing.T) {
+	entrants, seated := tournamentEntrants(30)
+	r, err := RunTournament(DefaultRules(), entrants, WithTableSize(5), WithAdvancing(2), WithTournamentSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every round seats the players left at tables of sizes differing by one at most.
+	left := len(entrants)
+	for round := 1; left > 0; round++ {
+		players, advanced, smallest, largest := 0, 0, 5, 0
+		for _, table := range r.Tables {
+			if table.Round != round {
+				continue
+			}
+			n := len(table.Standings)
+			players += n
+			advanced += len(table.Advanced)
+			smallest, largest = min(smallest, n), max(largest, n)
+			if len(table.Advanced) > 2 || table.Final && len(table.Advanced) > 0 {
+				t.Fatalf("expected at most 2 players to advance from a table, got\n%s", r)
+			}
+		}
+		if players != left || largest-smallest > 1 {
+			t.Fatalf("expected %d players at balanced tables in round %d, got\n%s", left, round, r)
+		}
+		left = advanced
+	}
+	last := r.Tables[len(r.Tables)-1]
+	if !last.Final {
+		t.Fatalf("expected the tournament to end with the final table, got\n%s", r)
+	}
+
+	if len(r.Leaderboard) != len(entrants) {
+		t.Fatalf("expected every entrant on the leaderboard, got\n%s", r)
+	}
+	names := map[string]bool{}
+	for i, s := range r.Leaderboard {
+		names[s.Name] = true
+		if s.Place != i+1 {
+			t.Fatalf("expected place %d, got %+v", i+1, s)
+		}
+		if i > 0 && s.Round > r.Leaderboard[i-1].Round {
+			t.Fatalf("expected players knocked out later to place higher, got\n%s", r)
+		}
+	}
+	if len(names) != len(entrants) {
+		t.Fatalf("expected every entrant once on the leaderboard, got\n%s", r)
+	}
+	for i, s := range last.Standings {
+		if r.Leaderboard[i] != s {
+			t.Fatalf("expected the final table to lead the leaderboard, got\n%s", r)
+		}
+	}
+
+	var played int
+	for _, table := range r.Tables {
+		for _, s := range table.Standings {
+			if s.Name == "Seated" {
+				played++
+			}
+		}
+	}
+	if seated.seats != played {
+		t.Fatalf("expected the AI told about its seat at %d tables, got %d", played, seated.seats)
+	}
+
+	again, err := RunTournament(DefaultRules(), func() []Entrant { e, _ := tournamentEntrants(30); return e }(), WithTableSize(5), WithAdvancing(2), WithTournamentSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, again) {
+		t.Fatalf("expected the same tournament for the same seed, got\n%s\nand\n%s", r, again)
+	}
+}
+
+func TestTournamentOptions(t *testing.T) {
+	entrants, _ := tournamentEntrants(4)
+	tests := []struct {
+		name     string
+		entrants []Entrant
+		options  []TournamentOptionFunc
+	}{
+		{"no entrants", nil, nil},
+		{"same name", append(entrants, entrants[1]), nil},
+		{"no AI", append(entrants, Entrant{Name: "Nobody"}), nil},
+		{"too many advance", entrants, []TournamentOptionFunc{WithTableSize(6), WithAdvancing(3)}},
+		{"no hands", entrants, []TournamentOptionFunc{WithHandsPerRound(0)}},
+		{"no chips", entrants, []TournamentOptionFunc{WithStartingChips(0)}},
+	}
+	for _, test := range tests {
+		if _, err := RunTournament(DefaultRules(), test.entrants, test.options...); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
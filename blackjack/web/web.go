@@ -0,0 +1,421 @@
+// Package web serves blackjack to browsers. Every visitor plays at a table of their
+// own, kept in a session identified by a cookie. Pages are rendered from an
+// html/template and post forms; the same requests are served as JSON under /api/.
+package web
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+)
+
+// cookieName is the cookie holding the session token.
+const cookieName = "blackjack_session"
+
+//go:embed templates static
+var files embed.FS
+
+// ErrBroke is returned when a player without any money left tries to play.
+var ErrBroke = errors.New("web: out of money, start over to play again")
+
+// Type Options is used to configure New.
+//
+// Balance: the bankroll every player starts with. Defaults to 1000. Set with WithBalance function
+//
+// SessionTimeout: time a session is kept without any request. Defaults to 30 minutes.
+// Set with WithSessionTimeout function
+//
+// MaxSessions: number of sessions kept at most, the least recently used one is dropped
+// to start another. Defaults to 10000. Set with WithMaxSessions function
+//
+// EngineOptions: options used to create the engine of every session. Set with WithEngineOptions function
+type Options struct {
+	Balance        float64
+	SessionTimeout time.Duration
+	MaxSessions    int
+	EngineOptions  []blackjack.EngineOptionFunc
+}
+
+// type OptionFunc acts a wrapper for functional
+// options used for configuration in New
+type OptionFunc func(*Options)
+
+// Option that sets the bankroll players start with.
+func WithBalance(balance float64) OptionFunc {
+	return func(o *Options) {
+		o.Balance = balance
+	}
+}
+
+// Option that sets the time an unused session is kept.
+func WithSessionTimeout(d time.Duration) OptionFunc {
+	return func(o *Options) {
+		o.SessionTimeout = d
+	}
+}
+
+// Option that sets the number of sessions kept at most.
+func WithMaxSessions(n int) OptionFunc {
+	return func(o *Options) {
+		o.MaxSessions = n
+	}
+}
+
+// Option used to pass options to the engine of every session, e.g blackjack.WithShoeSeed.
+func WithEngineOptions(options ...blackjack.EngineOptionFunc) OptionFunc {
+	return func(o *Options) {
+		o.EngineOptions = append(o.EngineOptions, options...)
+	}
+}
+
+// Server is an http.Handler serving the game.
+//
+//	GET  /              the table
+//	POST /bet           bets amount and deals
+//	POST /insurance     takes insurance, or even money, when take is set
+//	POST /action        plays action on the hand whose turn it is
+//	POST /reset         starts over with a new bankroll
+//	GET  /api/state     the View as JSON
+//	POST /api/...       the requests above with a JSON Request, answered with the View
+type Server struct {
+	rules    blackjack.Rules
+	options  Options
+	template *template.Template
+	mux      *http.ServeMux
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session is the table of one visitor.
+type session struct {
+	mu       sync.Mutex
+	engine   *blackjack.Engine
+	seat     int
+	balance  float64
+	broke    bool
+	result   *blackjack.SeatResult
+	flash    string
+	lastUsed time.Time
+}
+
+// Request is the body of the JSON requests. Only the field used by the request is read.
+type Request struct {
+	Amount int              `json:"amount"`
+	Take   bool             `json:"take"`
+	Action blackjack.Action `json:"action"`
+}
+
+// View is what a player sees of their table. Result is the settlement of the
+// last round until the next one starts.
+type View struct {
+	Rules   blackjack.Rules       `json:"rules"`
+	State   blackjack.State       `json:"state"`
+	Seat    int                   `json:"seat"`
+	Balance float64               `json:"balance"`
+	Broke   bool                  `json:"broke"`
+	Result  *blackjack.SeatResult `json:"result,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// CanBet reports whether the player can bet on a new round.
+func (v View) CanBet() bool {
+	return !v.Broke && (v.State.Phase == blackjack.PhaseIdle || v.State.Phase == blackjack.PhaseBetting)
+}
+
+// Insurance reports whether the player is asked for insurance.
+func (v View) Insurance() bool {
+	return v.State.Phase == blackjack.PhaseInsurance
+}
+
+// EvenMoney reports whether the insurance offered is even money on a blackjack.
+func (v View) EvenMoney() bool {
+	s, ok := v.State.Seat(v.Seat)
+	return ok && len(s.Hands) == 1 && len(s.Hands[0].Cards) == 2 && s.Hands[0].Value == 21
+}
+
+// Hands returns the hands the player holds.
+func (v View) Hands() []blackjack.HandState {
+	s, _ := v.State.Seat(v.Seat)
+	return s.Hands
+}
+
+// New creates a server for tables with the rules.
+func New(rules blackjack.Rules, options ...OptionFunc) (*Server, error) {
+	o := Options{Balance: 1000, SessionTimeout: 30 * time.Minute, MaxSessions: 10000}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.MaxSessions < 1 {
+		return nil, fmt.Errorf("web: at least one session must be kept, got %d", o.MaxSessions)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New("table.html").Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).ParseFS(files, "templates/table.html")
+	if err != nil {
+		return nil, err
+	}
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		rules:    rules,
+		options:  o,
+		template: tmpl,
+		mux:      http.NewServeMux(),
+		sessions: map[string]*session{},
+	}
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	s.mux.HandleFunc("GET /{$}", s.page)
+	s.mux.HandleFunc("GET /api/state", s.api(nil))
+	for path, do := range map[string]func(*session, Request) error{
+		"/bet":       s.bet,
+		"/insurance": s.insure,
+		"/action":    s.act,
+		"/reset":     s.reset,
+	} {
+		s.mux.HandleFunc("POST "+path, s.form(do))
+		s.mux.HandleFunc("POST /api"+path, s.api(do))
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// page renders the table, showing the error of the last form posted once.
+func (s *Server) page(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.session(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sess.mu.Lock()
+	view := sess.view()
+	view.Error, sess.flash = sess.flash, ""
+	sess.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.template.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// form handles a posted form and redirects back to the table.
+func (s *Server) form(do func(*session, Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sess, err := s.session(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var req Request
+		err = parseForm(r, &req)
+		sess.mu.Lock()
+		if err == nil {
+			err = do(sess, req)
+		}
+		if err != nil {
+			sess.flash = err.Error()
+		}
+		sess.mu.Unlock()
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// api handles a JSON request, a nil do only returns the view.
+func (s *Server) api(do func(*session, Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sess, err := s.session(w, r)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, View{Error: err.Error()})
+			return
+		}
+
+		var req Request
+		if do != nil && r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, View{Error: err.Error()})
+				return
+			}
+		}
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		status := http.StatusOK
+		if do != nil {
+			err = do(sess, req)
+		}
+		view := sess.view()
+		if err != nil {
+			status, view.Error = http.StatusBadRequest, err.Error()
+		}
+		writeJSON(w, status, view)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseForm(r *http.Request, req *Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if amount := r.PostForm.Get("amount"); amount != "" {
+		n, err := strconv.Atoi(amount)
+		if err != nil {
+			return errors.New("web: the bet must be a whole number")
+		}
+		req.Amount = n
+	}
+	if action := r.PostForm.Get("action"); action != "" {
+		if err := req.Action.UnmarshalText([]byte(action)); err != nil {
+			return err
+		}
+	}
+	req.Take = r.PostForm.Get("take") == "true"
+	return nil
+}
+
+// session returns the session of the request, starting a new one when the request
+// has none. Sessions left unused for longer than the timeout are dropped, and so is
+// the least recently used one when no more sessions can be kept.
+func (s *Server) session(w http.ResponseWriter, r *http.Request) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for token, sess := range s.sessions {
+		if now.Sub(sess.lastUsed) > s.options.SessionTimeout {
+			delete(s.sessions, token)
+		}
+	}
+
+	if c, err := r.Cookie(cookieName); err == nil {
+		if sess, ok := s.sessions[c.Value]; ok {
+			sess.lastUsed = now
+			return sess, nil
+		}
+	}
+
+	if len(s.sessions) >= s.options.MaxSessions {
+		var oldest string
+		for token, sess := range s.sessions {
+			if oldest == "" || sess.lastUsed.Before(s.sessions[oldest].lastUsed) {
+				oldest = token
+			}
+		}
+		delete(s.sessions, oldest)
+	}
+
+	sess := &session{lastUsed: now}
+	if err := s.reset(sess, Request{}); err != nil {
+		return nil, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	s.sessions[token] = sess
+	http.SetCookie(w, &http.Cookie{Name: cookieName, Value: token, Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	return sess, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// reset seats the player at a new table with the starting bankroll.
+func (s *Server) reset(sess *session, _ Request) error {
+	// Sessions reset concurrently, so they must not append to the shared options.
+	options := append(slices.Clone(s.options.EngineOptions), blackjack.WithSeats(1), blackjack.WithListener(sess.listen))
+	engine, err := blackjack.NewEngine(s.rules, options...)
+	if err != nil {
+		return err
+	}
+	seat, err := engine.AddPlayer("Player", nil, blackjack.WithBalance(s.options.Balance))
+	if err != nil {
+		return err
+	}
+	sess.engine, sess.seat, sess.balance = engine, seat, s.options.Balance
+	sess.broke, sess.result = false, nil
+	return nil
+}
+
+func (sess *session) listen(ev blackjack.Event) {
+	switch ev := ev.(type) {
+	case blackjack.RoundStarted:
+		sess.result = nil
+	case blackjack.RoundSettled:
+		for _, r := range ev.Results {
+			if r.Seat == sess.seat {
+				sess.result = &r
+				sess.balance += r.Net
+			}
+		}
+	case blackjack.PlayerLeft:
+		sess.broke = sess.broke || (ev.Seat == sess.seat && ev.Broke)
+	}
+}
+
+// bet starts a round if none is open, bets and deals the cards.
+func (s *Server) bet(sess *session, req Request) error {
+	if sess.broke {
+		return ErrBroke
+	}
+	e := sess.engine
+	if e.Phase() == blackjack.PhaseIdle {
+		if err := e.NewRound(); err != nil {
+			return err
+		}
+	}
+	if err := e.PlaceBet(sess.seat, req.Amount); err != nil {
+		return err
+	}
+	return e.Deal()
+}
+
+func (s *Server) insure(sess *session, req Request) error {
+	return sess.engine.Insure(sess.seat, req.Take)
+}
+
+func (s *Server) act(sess *session, req Request) error {
+	return sess.engine.Act(sess.seat, req.Action)
+}
+
+func (sess *session) view() View {
+	return View{
+		Rules:   sess.engine.Rules(),
+		State:   sess.engine.State(),
+		Seat:    sess.seat,
+		Balance: sess.balance,
+		Broke:   sess.broke,
+		Result:  sess.result,
+	}
+}
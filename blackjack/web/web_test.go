@@ -0,0 +1,186 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/blackjack"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *http.Client) {
+	t.Helper()
+	s, err := New(blackjack.DefaultRules(), WithBalance(100), WithEngineOptions(blackjack.WithShoeSeed(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts, &http.Client{Jar: jar}
+}
+
+func post(t *testing.T, c *http.Client, url string, req any) (View, int) {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var v View
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v, resp.StatusCode
+}
+
+func TestAPI(t *testing.T) {
+	ts, c := newTestServer(t)
+
+	v, status := post(t, c, ts.URL+"/api/bet", Request{Amount: 1000})
+	if status != http.StatusBadRequest || v.Error == "" {
+		t.Fatalf("expected a bet over the balance to fail, got %d %+v", status, v)
+	}
+
+	balance := 100.0
+	for round := 0; round < 20; round++ {
+		v, status = post(t, c, ts.URL+"/api/bet", Request{Amount: 10})
+		if status != http.StatusOK {
+			t.Fatalf("round %d: expected the bet to be dealt, got %d %s", round, status, v.Error)
+		}
+		for v.State.Phase != blackjack.PhaseIdle {
+			if v.Insurance() {
+				v, status = post(t, c, ts.URL+"/api/insurance", Request{Take: false})
+			} else {
+				action := blackjack.Stand
+				if hand := v.Hands()[v.State.Hand]; hand.Value < 17 && slices.Contains(v.State.Legal, blackjack.Hit) {
+					action = blackjack.Hit
+				}
+				v, status = post(t, c, ts.URL+"/api/action", Request{Action: action})
+			}
+			if status != http.StatusOK {
+				t.Fatalf("round %d: %s", round, v.Error)
+			}
+		}
+		if v.Result == nil {
+			t.Fatalf("round %d: expected the result of the round, got %+v", round, v)
+		}
+		balance += v.Result.Net
+		if v.Balance != balance {
+			t.Fatalf("round %d: expected a balance of %v, got %v", round, balance, v.Balance)
+		}
+	}
+
+	v, status = post(t, c, ts.URL+"/api/action", Request{Action: blackjack.Hit})
+	if status != http.StatusBadRequest || v.Error == "" {
+		t.Fatalf("expected acting between rounds to fail, got %d %+v", status, v)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ts, a := newTestServer(t)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &http.Client{Jar: jar}
+
+	if v, _ := post(t, a, ts.URL+"/api/bet", Request{Amount: 10}); v.State.Round != 1 {
+		t.Fatalf("expected the first round, got %+v", v.State)
+	}
+	resp, err := b.Get(ts.URL + "/api/state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var v View
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.State.Round != 0 || v.Balance != 100 {
+		t.Fatalf("expected a new table for a new session, got %+v", v)
+	}
+}
+
+func TestMaxSessions(t *testing.T) {
+	s, err := New(blackjack.DefaultRules(), WithMaxSessions(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cookies []*http.Cookie
+	for range 3 {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest("GET", "/api/state", nil))
+		cookies = append(cookies, w.Result().Cookies()...)
+	}
+	if len(cookies) != 3 || len(s.sessions) != 2 {
+		t.Fatalf("expected 2 of 3 sessions kept, got %d cookies and %d sessions", len(cookies), len(s.sessions))
+	}
+	if _, ok := s.sessions[cookies[0].Value]; ok {
+		t.Fatal("expected the least recently used session to be dropped")
+	}
+}
+
+func TestForms(t *testing.T) {
+	ts, c := newTestServer(t)
+
+	resp, err := c.PostForm(ts.URL+"/bet", url.Values{"amount": {"ten"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := readBody(t, resp)
+	if !strings.Contains(page, "whole number") {
+		t.Fatalf("expected the error on the page, got\n%s", page)
+	}
+
+	resp, err = c.PostForm(ts.URL+"/bet", url.Values{"amount": {"10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page = readBody(t, resp)
+	if strings.Contains(page, "whole number") {
+		t.Fatalf("expected the error to be shown once, got\n%s", page)
+	}
+	if !strings.Contains(page, "Your hand") || !strings.Contains(page, "Dealer") {
+		t.Fatalf("expected the hands on the page, got\n%s", page)
+	}
+	if !strings.Contains(page, `value="stand"`) && !strings.Contains(page, `action="/insurance"`) && !strings.Contains(page, `action="/bet"`) {
+		t.Fatalf("expected a form to play on, got\n%s", page)
+	}
+
+	resp, err = c.Get(ts.URL + "/static/styles.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the stylesheet, got %s", resp.Status)
+	}
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the page, got %s", resp.Status)
+	}
+	return string(b)
+}
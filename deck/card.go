@@ -63,7 +63,7 @@ func (c Card) String() string {
 // numJokers: option used to add n amount of Jokers to the deck. NewDeck does not add any Jokers
 // by default. Enable with WithJokers function
 //
-// filter: option that takes type func(Card) bool that returns true if the Card is to be kept and
+// filter: option that takes type func(Card) bool that returns true if the Card is to be removed and
 // false otherwise. Enable with WithFilter function
 //
 // comparator: option that takes tyep CardComparator that is used to sorts the deck using the underlying
@@ -73,12 +73,25 @@ func (c Card) String() string {
 // combineWith: option used to append Card slice to the existing 52 card standard deck. Note that
 // that combineWith is the first deck option exersized and will be effected by shuffle, filter, and
 // comparator deck options. Enable with WithCombineDeck function
+//
+// cardSet: option that defines which cards the deck is built from. Defaults to French52, the standard
+// 52 card deck. Enable with WithCardSet function
+//
+// rand: option that sets the random source used for shuffling. When nil the global
+// math/rand source is used. Enable with WithSeed or WithRand functions
+//
+// secure: option to shuffle with a single unbiased Fisher-Yates pass driven by crypto/rand
+// instead of math/rand. The rand option is ignored when enabled. Enable with WithSecureShuffle
+// function
 type DeckOptions struct {
 	Shuffle     bool
 	NumJokers   int
 	Filter      func(Card) bool
 	Comparator  CardComparator
 	CombineWith []Card
+	Rand        *rand.Rand
+	Secure      bool
+	CardSet     CardSet
 }
 
 // Option to shuffle the deck. Note that shuffling is done last
@@ -97,7 +110,7 @@ func WithJokers(numJokers int) OptionFunc {
 	}
 }
 
-// Option that takes type func(Card) bool that returns true if the Card is to be kept and
+// Option that takes type func(Card) bool that returns true if the Card is to be removed and
 // false otherwise.
 func WithFilter(filterFunc func(Card) bool) OptionFunc {
 	return func(o *DeckOptions) {
@@ -113,6 +126,14 @@ func WithSort(cmp CardComparator) OptionFunc {
 	}
 }
 
+// Option used to build the deck from a different set of cards than the standard
+// 52 card deck (e.g Piquet32 or Pinochle48). See CardSet for more information.
+func WithCardSet(set CardSet) OptionFunc {
+	return func(o *DeckOptions) {
+		o.CardSet = set
+	}
+}
+
 // Option used to append Card slice to the existing 52 card standard deck. Note that
 // that combineWith is the first deck option exersized and will be effected by shuffle, filter, and
 // comparator deck options.
@@ -122,6 +143,32 @@ func WithCombineDeck(deck []Card) OptionFunc {
 	}
 }
 
+// Option to shuffle the deck using crypto/rand. Use it for any game with real stakes,
+// since the order can not be predicted from previously dealt cards.
+func WithSecureShuffle() OptionFunc {
+	return func(o *DeckOptions) {
+		o.Shuffle = true
+		o.Secure = true
+	}
+}
+
+// Option used to shuffle with a source seeded by seed, so that the same seed
+// always produces the same order of cards.
+func WithSeed(seed int64) OptionFunc {
+	return func(o *DeckOptions) {
+		o.Rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Option used to shuffle with a caller supplied random source. Note that
+// the source is advanced every time it is used, so sharing it between decks
+// produces a different order for each deck.
+func WithRand(r *rand.Rand) OptionFunc {
+	return func(o *DeckOptions) {
+		o.Rand = r
+	}
+}
+
 const (
 	SPADE Suit = iota
 	DIAMOND
@@ -148,22 +195,24 @@ const (
 )
 
 // NewDeck generates and returns a slice of cards that serves as a deck.
-// By default NewDeck generates a standard 52 card deck sorted in conventional order
+// By default NewDeck generates a standard 52 card deck (see WithCardSet) sorted in conventional order
 // from aces to kings grouped by suits. Functional options provided in the package are used to
 // customize deck generation. See DeckOptions for more information.
 func NewDeck(options ...OptionFunc) []Card {
-	deckOptions := DeckOptions{
-		Shuffle:     false,
-		NumJokers:   0,
-		Filter:      nil,
-		Comparator:  DefaultComparator,
-		CombineWith: nil,
-	}
-	for _, option := range options {
-		option(&deckOptions)
+	deckOptions := newDeckOptions(options...)
+
+	deck := buildDeck(&deckOptions)
+	//shuffle
+	if deckOptions.Shuffle {
+		shuffle(deck, 3, &deckOptions)
 	}
 
-	deck := createStandardDeck()
+	return deck
+}
+
+// buildDeck creates a deck according to every option except shuffle.
+func buildDeck(deckOptions *DeckOptions) []Card {
+	deck := deckOptions.CardSet.Cards()
 
 	//combineWith
 	if deckOptions.CombineWith != nil {
@@ -179,10 +228,6 @@ func NewDeck(options ...OptionFunc) []Card {
 	}
 	//comparators
 	slices.SortStableFunc(deck, deckOptions.Comparator)
-	//shuffle
-	if deckOptions.Shuffle {
-		ShuffleDeck(deck, 3)
-	}
 
 	return deck
 }
@@ -198,21 +243,65 @@ func NewCard(suit Suit, cardType Type) Card {
 	return Card{suit, cardType}
 }
 
-func createStandardDeck() []Card {
-	deck := make([]Card, 0, 52)
-	for i := SPADE; i <= HEART; i++ {
-		for j := ACE; j <= KING; j++ {
-			deck = AddCards(deck, Card{i, j})
-		}
+// ShuffleDeck pseudo randomizes the deck n times. Only the randomness
+// options (WithSeed, WithRand, WithSecureShuffle) are used, any other option is ignored.
+func ShuffleDeck(deck []Card, n int, options ...OptionFunc) {
+	deckOptions := newDeckOptions(options...)
+	shuffle(deck, n, &deckOptions)
+}
+
+func newDeckOptions(options ...OptionFunc) DeckOptions {
+	deckOptions := DeckOptions{
+		Shuffle:     false,
+		NumJokers:   0,
+		Filter:      nil,
+		Comparator:  DefaultComparator,
+		CombineWith: nil,
+		Rand:        nil,
+		Secure:      false,
+		CardSet:     French52,
 	}
-	return deck
+	for _, option := range options {
+		option(&deckOptions)
+	}
+	return deckOptions
 }
 
-// ShuffleDeck pseudo randomizes the deck n times.
-func ShuffleDeck(deck []Card, n int) {
+func shuffle(deck []Card, n int, o *DeckOptions) {
+	if o.Secure {
+		// A single pass of an unbiased shuffle is already uniform.
+		n = min(n, 1)
+	}
 	for i := 0; i < n; i++ {
-		rand.Shuffle(len(deck), func(i, j int) {
+		o.shuffle(len(deck), func(i, j int) {
 			deck[i], deck[j] = deck[j], deck[i]
 		})
 	}
 }
+
+func (o *DeckOptions) shuffle(n int, swap func(i, j int)) {
+	if o.Secure {
+		secureShuffle(n, swap)
+		return
+	}
+	if o.Rand == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	o.Rand.Shuffle(n, swap)
+}
+
+func (o *DeckOptions) intn(n int) int {
+	if o.Secure {
+		return secureIntn(n)
+	}
+	if o.Rand == nil {
+		return rand.Intn(n)
+	}
+	return o.Rand.Intn(n)
+}
+
+func (o *DeckOptions) float64() float64 {
+	const precision = 1 << 53
+	return float64(o.intn(precision)) / precision
+}
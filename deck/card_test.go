@@ -0,0 +1,40 @@
+package deck
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestWithSeed(t *testing.T) {
+	t.Run("same seed", func(t *testing.T) {
+		a := NewDeck(WithShuffle(), WithSeed(42))
+		b := NewDeck(WithShuffle(), WithSeed(42))
+
+		if !slices.Equal(a, b) {
+			t.Fatalf("expected equal decks, got %v and %v", a, b)
+		}
+	})
+
+	t.Run("different seed", func(t *testing.T) {
+		a := NewDeck(WithShuffle(), WithSeed(42))
+		b := NewDeck(WithShuffle(), WithSeed(43))
+
+		if slices.Equal(a, b) {
+			t.Fatalf("expected different decks, got %v", a)
+		}
+	})
+}
+
+func TestShuffleDeckWithRand(t *testing.T) {
+	a, b := NewDeck(), NewDeck()
+	ShuffleDeck(a, 3, WithRand(rand.New(rand.NewSource(7))))
+	ShuffleDeck(b, 3, WithSeed(7))
+
+	if !slices.Equal(a, b) {
+		t.Fatalf("expected equal decks, got %v and %v", a, b)
+	}
+	if slices.Equal(a, NewDeck()) {
+		t.Fatal("expected deck to be shuffled")
+	}
+}
@@ -0,0 +1,72 @@
+package deck
+
+// CardSet describes the cards that make up a single deck: every type in Types
+// in every suit in Suits, repeated Copies times (a zero value counts as one copy).
+// Custom card sets can be passed to NewDeck and NewShoe with WithCardSet.
+type CardSet struct {
+	Name   string
+	Suits  []Suit
+	Types  []Type
+	Copies int
+}
+
+var frenchSuits = []Suit{SPADE, DIAMOND, CLUB, HEART}
+
+var (
+	// French52 is the standard 52 card deck, ace to king in every suit.
+	French52 = CardSet{
+		Name:  "French",
+		Suits: frenchSuits,
+		Types: []Type{ACE, TWO, THREE, FOUR, FIVE, SIX, SEVEN, EIGHT, NINE, TEN, JACK, QUEEN, KING},
+	}
+
+	// Piquet32 is the 32 card piquet deck, seven to ace in every suit. Also
+	// used for belote, skat and klaverjas.
+	Piquet32 = CardSet{
+		Name:  "Piquet",
+		Suits: frenchSuits,
+		Types: []Type{ACE, SEVEN, EIGHT, NINE, TEN, JACK, QUEEN, KING},
+	}
+
+	// Euchre24 is the 24 card euchre deck, nine to ace in every suit.
+	Euchre24 = CardSet{
+		Name:  "Euchre",
+		Suits: frenchSuits,
+		Types: []Type{ACE, NINE, TEN, JACK, QUEEN, KING},
+	}
+
+	// Spanish40 is the 40 card Spanish deck played with French suits. The
+	// sota, caballo and rey are represented by the jack, queen and king.
+	Spanish40 = CardSet{
+		Name:  "Spanish",
+		Suits: frenchSuits,
+		Types: []Type{ACE, TWO, THREE, FOUR, FIVE, SIX, SEVEN, JACK, QUEEN, KING},
+	}
+
+	// Pinochle48 is the 48 card pinochle deck, two copies of nine to ace in every suit.
+	Pinochle48 = CardSet{
+		Name:   "Pinochle",
+		Suits:  frenchSuits,
+		Types:  []Type{ACE, NINE, TEN, JACK, QUEEN, KING},
+		Copies: 2,
+	}
+)
+
+// Cards returns every card in the set grouped by suit.
+func (s CardSet) Cards() []Card {
+	copies := max(s.Copies, 1)
+	deck := make([]Card, 0, len(s.Suits)*len(s.Types)*copies)
+	for _, suit := range s.Suits {
+		for _, t := range s.Types {
+			for i := 0; i < copies; i++ {
+				deck = AddCards(deck, Card{suit, t})
+			}
+		}
+	}
+	return deck
+}
+
+// Size returns the number of cards in the set.
+func (s CardSet) Size() int {
+	return len(s.Suits) * len(s.Types) * max(s.Copies, 1)
+}
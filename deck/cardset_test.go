@@ -0,0 +1,43 @@
+package deck
+
+import "testing"
+
+func TestCardSets(t *testing.T) {
+	tests := []struct {
+		set  CardSet
+		size int
+	}{
+		{French52, 52},
+		{Piquet32, 32},
+		{Euchre24, 24},
+		{Spanish40, 40},
+		{Pinochle48, 48},
+	}
+
+	for _, test := range tests {
+		t.Run(test.set.Name, func(t *testing.T) {
+			d := NewDeck(WithCardSet(test.set), WithShuffle(), WithSeed(1))
+			if len(d) != test.size || test.set.Size() != test.size {
+				t.Fatalf("expected %d cards, got %d", test.size, len(d))
+			}
+		})
+	}
+}
+
+func TestCardSetOptions(t *testing.T) {
+	d := NewDeck(WithCardSet(Euchre24), WithJokers(1))
+	if len(d) != 25 {
+		t.Fatalf("expected 25 cards, got %d", len(d))
+	}
+	if d[0] != NewCard(SPADE, ACE) || d[1] != NewCard(SPADE, NINE) {
+		t.Fatalf("expected deck sorted by suit and type, got %v", d[:2])
+	}
+
+	s, err := NewShoe(2, 1, WithCardSet(Pinochle48))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != 96 {
+		t.Fatalf("expected 96 cards, got %d", s.Size())
+	}
+}
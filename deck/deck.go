@@ -0,0 +1,120 @@
+package deck
+
+import "fmt"
+
+// Deck is a pile of cards that are dealt from the top, along with the
+// discard pile of cards that have been burned or thrown away.
+// Use New or FromCards to create one.
+type Deck struct {
+	cards    []Card
+	discards []Card
+}
+
+// New creates a Deck from the cards generated by NewDeck with the given options.
+func New(options ...OptionFunc) *Deck {
+	return &Deck{cards: NewDeck(options...)}
+}
+
+// FromCards creates a Deck from a copy of cards, where the first card is the top of the deck.
+func FromCards(cards []Card) *Deck {
+	return &Deck{cards: append([]Card(nil), cards...)}
+}
+
+// Len returns the number of cards left in the deck.
+func (d *Deck) Len() int {
+	return len(d.cards)
+}
+
+// Cards returns a copy of the cards left in the deck, starting from the top.
+func (d *Deck) Cards() []Card {
+	return append([]Card(nil), d.cards...)
+}
+
+// Draw removes and returns the top card of the deck. ErrEmpty is returned if there are no cards left.
+func (d *Deck) Draw() (Card, error) {
+	if len(d.cards) == 0 {
+		return Card{}, ErrEmpty
+	}
+	c := d.cards[0]
+	d.cards = d.cards[1:]
+	return c, nil
+}
+
+// DrawN removes and returns the top n cards of the deck. If there are fewer than n cards
+// left no card is drawn and an error wrapping ErrEmpty is returned.
+func (d *Deck) DrawN(n int) ([]Card, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("deck: can not draw %d cards", n)
+	}
+	if n > len(d.cards) {
+		return nil, fmt.Errorf("%w: drawing %d cards with %d left", ErrEmpty, n, len(d.cards))
+	}
+	cards := append([]Card(nil), d.cards[:n]...)
+	d.cards = d.cards[n:]
+	return cards, nil
+}
+
+// Peek returns the top card of the deck without removing it.
+func (d *Deck) Peek() (Card, error) {
+	if len(d.cards) == 0 {
+		return Card{}, ErrEmpty
+	}
+	return d.cards[0], nil
+}
+
+// Burn moves the top card of the deck to the discard pile and returns it.
+func (d *Deck) Burn() (Card, error) {
+	c, err := d.Draw()
+	if err != nil {
+		return Card{}, err
+	}
+	d.Discard(c)
+	return c, nil
+}
+
+// Deal deals n cards to each of the hands one card at a time, the way a dealer
+// goes around the table. If there are not enough cards for every hand no card is
+// dealt and an error wrapping ErrEmpty is returned.
+func (d *Deck) Deal(hands, n int) ([][]Card, error) {
+	if hands < 0 || n < 0 {
+		return nil, fmt.Errorf("deck: can not deal %d cards to %d hands", n, hands)
+	}
+	if hands*n > len(d.cards) {
+		return nil, fmt.Errorf("%w: dealing %d cards to %d hands with %d left", ErrEmpty, n, hands, len(d.cards))
+	}
+
+	dealt := make([][]Card, hands)
+	for i := range dealt {
+		dealt[i] = make([]Card, 0, n)
+	}
+	for i := 0; i < n; i++ {
+		for h := range dealt {
+			dealt[h] = AddCards(dealt[h], d.cards[0])
+			d.cards = d.cards[1:]
+		}
+	}
+	return dealt, nil
+}
+
+// ReturnToBottom puts cards back at the bottom of the deck in the given order.
+func (d *Deck) ReturnToBottom(cards ...Card) {
+	d.cards = AddCards(d.cards, cards...)
+}
+
+// Discard adds cards to the discard pile.
+func (d *Deck) Discard(cards ...Card) {
+	d.discards = AddCards(d.discards, cards...)
+}
+
+// Discards returns a copy of the discard pile, in the order the cards were discarded.
+func (d *Deck) Discards() []Card {
+	return append([]Card(nil), d.discards...)
+}
+
+// Reshuffle returns the discard pile to the deck and shuffles it. Only the
+// randomness options (WithSeed, WithRand, WithSecureShuffle) are used.
+func (d *Deck) Reshuffle(options ...OptionFunc) {
+	d.cards = AddCards(d.cards, d.discards...)
+	d.discards = nil
+	ShuffleDeck(d.cards, 3, options...)
+}
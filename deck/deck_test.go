@@ -0,0 +1,75 @@
+package deck
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDeckDraw(t *testing.T) {
+	d := New()
+
+	c, err := d.Peek()
+	if err != nil || c != NewCard(SPADE, ACE) {
+		t.Fatalf("expected ACE of SPADE on top, got %s (%v)", c, err)
+	}
+
+	cards, err := d.DrawN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cards, NewDeck()[:3]) || d.Len() != 49 {
+		t.Fatalf("expected top 3 cards, got %v with %d left", cards, d.Len())
+	}
+
+	if _, err := d.DrawN(50); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	if d.Len() != 49 {
+		t.Fatalf("expected failed draw to leave 49 cards, got %d", d.Len())
+	}
+
+	for d.Len() > 0 {
+		d.Draw()
+	}
+	if _, err := d.Draw(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	if _, err := d.Peek(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestDeckDeal(t *testing.T) {
+	d := FromCards(NewDeck()[:6])
+
+	hands, err := d.Deal(3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hands[0][0] != NewCard(SPADE, ACE) || hands[0][1] != NewCard(SPADE, FOUR) {
+		t.Fatalf("expected cards to be dealt round-robin, got %v", hands)
+	}
+
+	d.ReturnToBottom(hands[0]...)
+	if _, err := d.Deal(3, 1); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestDeckBurnAndReshuffle(t *testing.T) {
+	d := New()
+
+	burned, err := d.Burn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if discards := d.Discards(); len(discards) != 1 || discards[0] != burned {
+		t.Fatalf("expected %s in discard pile, got %v", burned, discards)
+	}
+
+	d.Reshuffle(WithSeed(1))
+	if d.Len() != 52 || len(d.Discards()) != 0 {
+		t.Fatalf("expected discards back in deck, got %d cards", d.Len())
+	}
+}
@@ -0,0 +1,178 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Short notation uses one character for the type followed by one character for
+// the suit, e.g. "As" for the ace of spades and "Td" for the ten of diamonds.
+// Jokers are written as "Jk".
+const (
+	typeChars  = "?A23456789TJQK"
+	suitChars  = "sdch"
+	jokerShort = "Jk"
+)
+
+// Short returns the card in short notation (e.g "As", "Td", "Jk").
+// Cards with an unknown suit or type are returned as "??".
+func (c Card) Short() string {
+	if c.Suit == JOKER {
+		return jokerShort
+	}
+	if !c.valid() {
+		return "??"
+	}
+	return string([]byte{typeChars[c.Type], suitChars[c.Suit]})
+}
+
+// ParseCard parses a card written in short notation. The type is case insensitive
+// and "10" is accepted in place of "T".
+func ParseCard(s string) (Card, error) {
+	if strings.EqualFold(s, jokerShort) {
+		return NewCard(JOKER, NONE), nil
+	}
+	if len(s) == 3 && s[:2] == "10" {
+		s = "T" + s[2:]
+	}
+	if len(s) != 2 {
+		return Card{}, fmt.Errorf("deck: invalid card %q", s)
+	}
+
+	t := strings.IndexByte(typeChars[1:], strings.ToUpper(s[:1])[0])
+	suit := strings.IndexByte(suitChars, strings.ToLower(s[1:])[0])
+	if t < 0 || suit < 0 {
+		return Card{}, fmt.Errorf("deck: invalid card %q", s)
+	}
+
+	return NewCard(Suit(suit), Type(t+1)), nil
+}
+
+// FormatDeck returns the cards in short notation separated by spaces.
+func FormatDeck(deck []Card) string {
+	var b strings.Builder
+	for i, c := range deck {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(c.Short())
+	}
+	return b.String()
+}
+
+// ParseDeck parses cards in short notation separated by whitespace.
+func ParseDeck(s string) ([]Card, error) {
+	fields := strings.Fields(s)
+	deck := make([]Card, 0, len(fields))
+	for _, f := range fields {
+		c, err := ParseCard(f)
+		if err != nil {
+			return nil, err
+		}
+		deck = AddCards(deck, c)
+	}
+	return deck, nil
+}
+
+// MarshalText implements encoding.TextMarshaler using short notation.
+func (c Card) MarshalText() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("deck: cannot marshal invalid card %v", c)
+	}
+	return []byte(c.Short()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using short notation.
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// MarshalJSON encodes the card as a JSON string in short notation.
+func (c Card) MarshalJSON() ([]byte, error) {
+	text, err := c.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a card from a JSON string in short notation.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("deck: card must be a JSON string: %w", err)
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// MarshalBinary encodes the card in a single byte, the suit in the
+// high four bits and the type in the low four bits.
+func (c Card) MarshalBinary() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("deck: cannot marshal invalid card %v", c)
+	}
+	return []byte{c.byte()}, nil
+}
+
+// UnmarshalBinary decodes a card encoded with MarshalBinary.
+func (c *Card) UnmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return fmt.Errorf("deck: card must be encoded in 1 byte, got %d", len(data))
+	}
+	card, err := cardFromByte(data[0])
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// EncodeDeck encodes the deck with one byte per card, see Card.MarshalBinary.
+func EncodeDeck(deck []Card) ([]byte, error) {
+	data := make([]byte, len(deck))
+	for i, c := range deck {
+		if !c.valid() {
+			return nil, fmt.Errorf("deck: cannot encode invalid card %v at position %d", c, i)
+		}
+		data[i] = c.byte()
+	}
+	return data, nil
+}
+
+// DecodeDeck decodes a deck encoded with EncodeDeck.
+func DecodeDeck(data []byte) ([]Card, error) {
+	deck := make([]Card, len(data))
+	for i, b := range data {
+		c, err := cardFromByte(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w at position %d", err, i)
+		}
+		deck[i] = c
+	}
+	return deck, nil
+}
+
+func (c Card) valid() bool {
+	if c.Suit == JOKER {
+		return c.Type == NONE
+	}
+	return c.Suit >= SPADE && c.Suit <= HEART && c.Type >= ACE && c.Type <= KING
+}
+
+func (c Card) byte() byte {
+	return byte(c.Suit)<<4 | byte(c.Type)
+}
+
+func cardFromByte(b byte) (Card, error) {
+	c := NewCard(Suit(b>>4), Type(b&0x0f))
+	if !c.valid() {
+		return Card{}, fmt.Errorf("deck: invalid card byte %#02x", b)
+	}
+	return c, nil
+}
@@ -0,0 +1,88 @@
+package deck
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	tests := map[string]Card{
+		"As":  NewCard(SPADE, ACE),
+		"Td":  NewCard(DIAMOND, TEN),
+		"10d": NewCard(DIAMOND, TEN),
+		"kh":  NewCard(HEART, KING),
+		"2c":  NewCard(CLUB, TWO),
+		"Jk":  NewCard(JOKER, NONE),
+	}
+
+	for s, want := range tests {
+		got, err := ParseCard(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %s, got %s", s, want, got)
+		}
+	}
+
+	for _, s := range []string{"", "A", "Ax", "1s", "Asd"} {
+		if _, err := ParseCard(s); err == nil {
+			t.Fatalf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestDeckRoundTrip(t *testing.T) {
+	d := NewDeck(WithJokers(2), WithShuffle(), WithSeed(9))
+
+	t.Run("text", func(t *testing.T) {
+		got, err := ParseDeck(FormatDeck(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, d) {
+			t.Fatalf("expected %v, got %v", d, got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Card
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, d) {
+			t.Fatalf("expected %v, got %v", d, got)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := EncodeDeck(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != len(d) {
+			t.Fatalf("expected %d bytes, got %d", len(d), len(data))
+		}
+		got, err := DecodeDeck(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, d) {
+			t.Fatalf("expected %v, got %v", d, got)
+		}
+	})
+}
+
+func TestInvalidCardEncoding(t *testing.T) {
+	if _, err := (Card{Suit: HEART, Type: NONE}).MarshalText(); err == nil {
+		t.Fatal("expected error marshalling invalid card")
+	}
+	if _, err := DecodeDeck([]byte{0xff}); err == nil {
+		t.Fatal("expected error decoding invalid byte")
+	}
+}
@@ -0,0 +1,97 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// UniformityReport is the result of CheckUniformity. For every position in the deck
+// it holds the chi-square statistic of how often each card landed there, along with
+// the p-value of that statistic under the hypothesis that the shuffle is uniform.
+type UniformityReport struct {
+	Trials    int
+	DeckSize  int
+	ChiSquare []float64
+	PValues   []float64
+}
+
+// MinPValue returns the smallest p-value over all positions.
+func (r UniformityReport) MinPValue() float64 {
+	if len(r.PValues) == 0 {
+		return 1
+	}
+	return slices.Min(r.PValues)
+}
+
+// IsUniform reports whether no position rejects the uniform hypothesis at the
+// significance level alpha. The level is split across all positions
+// (Bonferroni correction) so alpha is the chance of a false alarm for the whole deck.
+func (r UniformityReport) IsUniform(alpha float64) bool {
+	return r.MinPValue() >= alpha/float64(len(r.PValues))
+}
+
+func (r UniformityReport) String() string {
+	return fmt.Sprintf("%d trials over %d positions, min p-value %.6f", r.Trials, r.DeckSize, r.MinPValue())
+}
+
+// CheckUniformity shuffles a copy of deck trials times using shuffle and counts
+// which card ends up in each position. A fair shuffle places every card in every
+// position with equal probability. Cards in deck must be distinct.
+//
+// For the statistics to be meaningful trials should be at least 5 times the deck size.
+func CheckUniformity(deck []Card, trials int, shuffle func([]Card)) UniformityReport {
+	size := len(deck)
+	index := make(map[Card]int, size)
+	for i, c := range deck {
+		if _, ok := index[c]; ok {
+			panic(fmt.Sprintf("deck: CheckUniformity requires distinct cards, found %s twice", c))
+		}
+		index[c] = i
+	}
+
+	counts := make([][]int, size)
+	for i := range counts {
+		counts[i] = make([]int, size)
+	}
+
+	cards := make([]Card, size)
+	for t := 0; t < trials; t++ {
+		copy(cards, deck)
+		shuffle(cards)
+		for pos, c := range cards {
+			counts[pos][index[c]]++
+		}
+	}
+
+	report := UniformityReport{
+		Trials:    trials,
+		DeckSize:  size,
+		ChiSquare: make([]float64, size),
+		PValues:   make([]float64, size),
+	}
+	expected := float64(trials) / float64(size)
+	for pos := range counts {
+		var chi float64
+		for _, n := range counts[pos] {
+			d := float64(n) - expected
+			chi += d * d / expected
+		}
+		report.ChiSquare[pos] = chi
+		report.PValues[pos] = chiSquareSurvival(chi, size-1)
+	}
+
+	return report
+}
+
+// chiSquareSurvival approximates P(X >= x) for a chi-square distribution with k
+// degrees of freedom using the Wilson-Hilferty transformation.
+func chiSquareSurvival(x float64, k int) float64 {
+	if k <= 0 {
+		return 1
+	}
+	df := float64(k)
+	v := 2 / (9 * df)
+	z := (math.Cbrt(x/df) - (1 - v)) / math.Sqrt(v)
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
@@ -0,0 +1,63 @@
+package deck
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const (
+	uniformityTrials = 20000
+	uniformityAlpha  = 0.001
+)
+
+func TestShuffleDeckUniformity(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	report := CheckUniformity(NewDeck(), uniformityTrials, func(cards []Card) {
+		ShuffleDeck(cards, 3, WithRand(r))
+	})
+
+	if !report.IsUniform(uniformityAlpha) {
+		t.Fatalf("expected uniform shuffle, got %s", report)
+	}
+}
+
+func TestSecureShuffleUniformity(t *testing.T) {
+	report := CheckUniformity(NewDeck(), uniformityTrials, func(cards []Card) {
+		ShuffleDeck(cards, 1, WithSecureShuffle())
+	})
+
+	if !report.IsUniform(uniformityAlpha) {
+		t.Fatalf("expected uniform shuffle, got %s", report)
+	}
+}
+
+func TestBiasedShuffleIsDetected(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	report := CheckUniformity(NewDeck(), uniformityTrials, func(cards []Card) {
+		// Only swapping neighbours leaves most cards close to where they started.
+		for i := 0; i+1 < len(cards); i += 2 {
+			if r.Intn(2) == 0 {
+				cards[i], cards[i+1] = cards[i+1], cards[i]
+			}
+		}
+	})
+
+	if report.IsUniform(uniformityAlpha) {
+		t.Fatalf("expected biased shuffle to be detected, got %s", report)
+	}
+}
+
+func TestWithSecureShuffle(t *testing.T) {
+	d := NewDeck(WithSecureShuffle())
+
+	if len(d) != 52 {
+		t.Fatalf("expected 52 cards, got %d", len(d))
+	}
+	seen := make(map[Card]bool)
+	for _, c := range d {
+		if seen[c] {
+			t.Fatalf("card %s dealt twice", c)
+		}
+		seen[c] = true
+	}
+}
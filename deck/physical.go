@@ -0,0 +1,100 @@
+package deck
+
+// The shuffles in this file model the way people shuffle a physical deck, and unlike
+// ShuffleDeck they leave some of the previous order behind. The first card of
+// the slice is treated as the top of the deck. Every shuffle works in place and
+// honours the randomness options (WithSeed, WithRand, WithSecureShuffle).
+
+const (
+	// Probability that an overhand shuffle breaks off a packet between two cards.
+	overhandCutProbability = 0.2
+	// Average number of packets pulled out in a strip shuffle.
+	stripPackets = 5
+)
+
+// RiffleShuffle performs a riffle shuffle following the Gilbert-Shannon-Reeds model.
+// The deck is cut in two with a binomially distributed cut, then cards are
+// dropped from either half with a probability proportional to the size of that half.
+// About seven riffles are needed to mix a 52 card deck.
+func RiffleShuffle(deck []Card, options ...OptionFunc) {
+	o := newDeckOptions(options...)
+	riffle(deck, &o)
+}
+
+// OverhandShuffle performs an overhand shuffle. Small packets are slid off the
+// top of the deck into the other hand, so every packet keeps its order while the
+// order of the packets is reversed.
+func OverhandShuffle(deck []Card, options ...OptionFunc) {
+	o := newDeckOptions(options...)
+	reversePackets(deck, overhandCutProbability, &o)
+}
+
+// StripShuffle performs a strip shuffle. A few large packets are pulled off
+// the top of the deck onto the table, reversing the order of the packets.
+func StripShuffle(deck []Card, options ...OptionFunc) {
+	if len(deck) < 2 {
+		return
+	}
+	o := newDeckOptions(options...)
+	reversePackets(deck, min(1, stripPackets/float64(len(deck))), &o)
+}
+
+// Cut moves the cards above a random point in the deck to the bottom.
+// At least one card is always moved and at least one card is always left on top.
+func Cut(deck []Card, options ...OptionFunc) {
+	if len(deck) < 2 {
+		return
+	}
+	o := newDeckOptions(options...)
+	CutAt(deck, 1+o.intn(len(deck)-1))
+}
+
+// CutAt moves the top n cards of the deck to the bottom.
+func CutAt(deck []Card, n int) {
+	if n <= 0 || n >= len(deck) {
+		return
+	}
+	top := append([]Card(nil), deck[:n]...)
+	copy(deck, deck[n:])
+	copy(deck[len(deck)-n:], top)
+}
+
+func riffle(deck []Card, o *DeckOptions) {
+	n := len(deck)
+	cut := 0
+	for i := 0; i < n; i++ {
+		cut += o.intn(2)
+	}
+
+	left := append([]Card(nil), deck[:cut]...)
+	right := append([]Card(nil), deck[cut:]...)
+	for i := range deck {
+		l, r := len(left), len(right)
+		if o.intn(l+r) < l {
+			deck[i], left = left[0], left[1:]
+		} else {
+			deck[i], right = right[0], right[1:]
+		}
+	}
+}
+
+func reversePackets(deck []Card, p float64, o *DeckOptions) {
+	n := len(deck)
+	if n < 2 {
+		return
+	}
+
+	pile := make([]Card, n)
+	end, start := n, 0
+	for i := 1; i <= n; i++ {
+		if i < n && o.float64() >= p {
+			continue
+		}
+		// The packet deck[start:i] lands on top of everything moved so far.
+		size := i - start
+		copy(pile[end-size:end], deck[start:i])
+		end -= size
+		start = i
+	}
+	copy(deck, pile)
+}
@@ -0,0 +1,61 @@
+package deck
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPhysicalShufflesKeepCards(t *testing.T) {
+	shuffles := map[string]func([]Card, ...OptionFunc){
+		"riffle":   RiffleShuffle,
+		"overhand": OverhandShuffle,
+		"strip":    StripShuffle,
+		"cut":      Cut,
+	}
+
+	for name, shuffle := range shuffles {
+		t.Run(name, func(t *testing.T) {
+			d := NewDeck()
+			shuffle(d, WithSeed(3))
+
+			if slices.Equal(d, NewDeck()) {
+				t.Fatal("expected deck order to change")
+			}
+			slices.SortFunc(d, DefaultComparator)
+			if !slices.Equal(d, NewDeck()) {
+				t.Fatalf("expected same cards after shuffle, got %v", d)
+			}
+		})
+	}
+}
+
+func TestCutAt(t *testing.T) {
+	d := NewDeck()
+	CutAt(d, 13)
+
+	if d[0] != NewCard(DIAMOND, ACE) || d[51] != NewCard(SPADE, KING) {
+		t.Fatalf("expected spades to be moved to the bottom, got %v", d)
+	}
+}
+
+func TestRiffleShuffleKeepsRisingSequences(t *testing.T) {
+	d := NewDeck()
+	RiffleShuffle(d, WithSeed(5))
+
+	// A single riffle interleaves two packets, so the original order
+	// splits into at most two rising sequences.
+	position := make(map[Card]int)
+	for i, c := range d {
+		position[c] = i
+	}
+	sorted := NewDeck()
+	sequences := 1
+	for i := 1; i < len(sorted); i++ {
+		if position[sorted[i]] < position[sorted[i-1]] {
+			sequences++
+		}
+	}
+	if sequences > 2 {
+		t.Fatalf("expected at most 2 rising sequences, got %d", sequences)
+	}
+}
@@ -0,0 +1,160 @@
+package deck
+
+import (
+	"strings"
+)
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+var suitSymbols = [...]string{"♠", "♦", "♣", "♥"}
+
+// Offsets of each type in the Unicode playing cards block. The block has a
+// Knight between the Jack and Queen that is not part of the deck.
+var glyphTypes = [...]rune{0, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9, 0xa, 0xb, 0xd, 0xe}
+
+// Start of every suit in the Unicode playing cards block.
+var glyphSuits = [...]rune{0x1f0a0, 0x1f0c0, 0x1f0d0, 0x1f0b0}
+
+const (
+	glyphJoker = '\U0001f0cf'
+	glyphBack  = '\U0001f0a0'
+)
+
+// Symbol returns the suit symbol (♠♦♣♥) or "★" for jokers.
+func (s Suit) Symbol() string {
+	if s >= SPADE && s <= HEART {
+		return suitSymbols[s]
+	}
+	return "★"
+}
+
+// IsRed reports whether the suit is printed in red.
+func (s Suit) IsRed() bool {
+	return s == DIAMOND || s == HEART
+}
+
+// Glyph returns the card as a single character from the Unicode
+// playing cards block (e.g 🂡 for the ace of spades).
+func (c Card) Glyph() string {
+	if c.Suit == JOKER {
+		return string(glyphJoker)
+	}
+	if !c.valid() {
+		return string(glyphBack)
+	}
+	return string(glyphSuits[c.Suit] + glyphTypes[c.Type])
+}
+
+// Symbol returns the card type followed by its suit symbol (e.g "10♥").
+func (c Card) Symbol() string {
+	if c.Suit == JOKER {
+		return "JK★"
+	}
+	return c.rank() + c.Suit.Symbol()
+}
+
+// Colored returns Symbol wrapped in ANSI escape codes that print red suits in red.
+func (c Card) Colored() string {
+	return colorize(c, c.Symbol(), true)
+}
+
+// Type RenderOptions is used to configure RenderCards.
+//
+// Color: option to print red suits in red using ANSI escape codes. Enable with WithColor function
+//
+// FaceDown: indices of the cards that are drawn face down. Enable with WithFaceDown function
+type RenderOptions struct {
+	Color    bool
+	FaceDown []int
+}
+
+// type RenderOptionFunc acts a wrapper for functional
+// options used for configuration in RenderCards
+type RenderOptionFunc func(*RenderOptions)
+
+// Option to print red suits in red using ANSI escape codes.
+func WithColor() RenderOptionFunc {
+	return func(o *RenderOptions) {
+		o.Color = true
+	}
+}
+
+// Option to draw the cards at the given indices face down, such as a dealer's hole card.
+func WithFaceDown(indices ...int) RenderOptionFunc {
+	return func(o *RenderOptions) {
+		o.FaceDown = append(o.FaceDown, indices...)
+	}
+}
+
+// RenderCards draws the cards as ASCII-art boxes placed side by side, ready to be printed
+// to a terminal. Every line ends with a newline.
+//
+//	+-----+ +-----+
+//	|A    | |10   |
+//	|  ♠  | |  ♥  |
+//	|    A| |   10|
+//	+-----+ +-----+
+func RenderCards(cards []Card, options ...RenderOptionFunc) string {
+	var o RenderOptions
+	for _, option := range options {
+		option(&o)
+	}
+
+	const height = 5
+	var lines [height][]string
+	for i, c := range cards {
+		var art [height]string
+		faceDown := false
+		for _, j := range o.FaceDown {
+			faceDown = faceDown || i == j
+		}
+		if faceDown {
+			art = [height]string{"+-----+", "|/////|", "|/////|", "|/////|", "+-----+"}
+		} else {
+			rank, symbol := c.rank(), c.Suit.Symbol()
+			art = [height]string{
+				"+-----+",
+				"|" + colorize(c, rank, o.Color) + strings.Repeat(" ", 5-len(rank)) + "|",
+				"|  " + colorize(c, symbol, o.Color) + "  |",
+				"|" + strings.Repeat(" ", 5-len(rank)) + colorize(c, rank, o.Color) + "|",
+				"+-----+",
+			}
+		}
+		for l := range lines {
+			lines[l] = append(lines[l], art[l])
+		}
+	}
+
+	var b strings.Builder
+	for _, l := range lines {
+		if len(l) == 0 {
+			continue
+		}
+		b.WriteString(strings.Join(l, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// rank returns the short name of the card type (e.g "A", "10", "K").
+func (c Card) rank() string {
+	switch {
+	case c.Suit == JOKER:
+		return "JK"
+	case c.Type == TEN:
+		return "10"
+	case c.valid():
+		return typeChars[c.Type : c.Type+1]
+	}
+	return "?"
+}
+
+func colorize(c Card, s string, color bool) string {
+	if !color || !c.Suit.IsRed() {
+		return s
+	}
+	return ansiRed + s + ansiReset
+}
@@ -0,0 +1,43 @@
+package deck
+
+import "testing"
+
+func TestGlyph(t *testing.T) {
+	tests := map[Card]string{
+		NewCard(SPADE, ACE):    "🂡",
+		NewCard(HEART, TEN):    "🂺",
+		NewCard(DIAMOND, KING): "🃎",
+		NewCard(CLUB, QUEEN):   "🃝",
+		NewCard(JOKER, NONE):   "🃏",
+	}
+
+	for c, want := range tests {
+		if got := c.Glyph(); got != want {
+			t.Fatalf("%s: expected %s, got %s", c, want, got)
+		}
+	}
+}
+
+func TestRenderCards(t *testing.T) {
+	cards := []Card{NewCard(SPADE, ACE), NewCard(HEART, TEN)}
+
+	want := "" +
+		"+-----+ +-----+\n" +
+		"|A    | |/////|\n" +
+		"|  ♠  | |/////|\n" +
+		"|    A| |/////|\n" +
+		"+-----+ +-----+\n"
+	if got := RenderCards(cards, WithFaceDown(1)); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	want = "" +
+		"+-----+ +-----+\n" +
+		"|A    | |\x1b[31m10\x1b[0m   |\n" +
+		"|  ♠  | |  \x1b[31m♥\x1b[0m  |\n" +
+		"|    A| |   \x1b[31m10\x1b[0m|\n" +
+		"+-----+ +-----+\n"
+	if got := RenderCards(cards, WithColor()); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,38 @@
+package deck
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+)
+
+// secureShuffle performs a Fisher-Yates shuffle of n elements using
+// crypto/rand as the source of randomness.
+func secureShuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := secureIntn(i + 1)
+		swap(i, j)
+	}
+}
+
+// secureIntn returns a uniform random number in [0, n) read from crypto/rand.
+// Values that fall in the incomplete range at the top of uint64 are rejected
+// so that no result is more likely than another.
+func secureIntn(n int) int {
+	if n <= 0 {
+		panic("deck: invalid argument to secureIntn")
+	}
+	bound := uint64(n)
+	limit := math.MaxUint64 - math.MaxUint64%bound
+
+	var buf [8]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			panic("deck: crypto/rand unavailable: " + err.Error())
+		}
+		v := binary.LittleEndian.Uint64(buf[:])
+		if v < limit {
+			return int(v % bound)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package deck
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrEmpty is returned when drawing from a deck or shoe that has no cards left.
+var ErrEmpty = errors.New("deck: no cards left")
+
+// Shoe combines several decks into a single pile that cards are dealt from,
+// the way casinos deal blackjack. A cut card is placed at the configured
+// penetration and once it is reached the shoe reports that a reshuffle is due.
+// Cards can still be drawn past the cut card so that a round in progress can finish.
+type Shoe struct {
+	cards       []Card
+	next        int
+	cut         int
+	decks       int
+	penetration float64
+	options     DeckOptions
+}
+
+// NewShoe creates a shoe made of n decks with the cut card placed after
+// penetration (0 < penetration <= 1) of the cards have been dealt. Every deck
+// is built with the given options, except that shuffling is done on the
+// shoe as a whole.
+func NewShoe(n int, penetration float64, options ...OptionFunc) (*Shoe, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("deck: shoe needs at least one deck, got %d", n)
+	}
+	if penetration <= 0 || penetration > 1 || math.IsNaN(penetration) {
+		return nil, fmt.Errorf("deck: penetration must be in (0, 1], got %v", penetration)
+	}
+
+	s := &Shoe{
+		decks:       n,
+		penetration: penetration,
+		options:     newDeckOptions(options...),
+	}
+	for i := 0; i < n; i++ {
+		s.cards = AddCards(s.cards, buildDeck(&s.options)...)
+	}
+	s.placeCutCard()
+
+	if s.options.Shuffle {
+		s.Shuffle()
+	}
+
+	return s, nil
+}
+
+// NewShoeFromCards creates a shoe that deals cards in the given order, such as a
+// shoe that was recorded earlier. decks is the number of decks the cards make up
+// and is only used by DecksRemaining.
+func NewShoeFromCards(cards []Card, decks int, penetration float64, options ...OptionFunc) (*Shoe, error) {
+	if len(cards) == 0 {
+		return nil, errors.New("deck: shoe needs at least one card")
+	}
+	if decks < 1 {
+		return nil, fmt.Errorf("deck: shoe needs at least one deck, got %d", decks)
+	}
+	if penetration <= 0 || penetration > 1 || math.IsNaN(penetration) {
+		return nil, fmt.Errorf("deck: penetration must be in (0, 1], got %v", penetration)
+	}
+
+	s := &Shoe{
+		cards:       append([]Card(nil), cards...),
+		decks:       decks,
+		penetration: penetration,
+		options:     newDeckOptions(options...),
+	}
+	s.placeCutCard()
+
+	return s, nil
+}
+
+// Cards returns a copy of every card in the shoe in the order they are dealt,
+// including the cards already dealt since the last shuffle.
+func (s *Shoe) Cards() []Card {
+	return append([]Card(nil), s.cards...)
+}
+
+// Shuffle gathers every card back into the shoe and shuffles it. The cut card
+// stays at the same position.
+func (s *Shoe) Shuffle() {
+	shuffle(s.cards, 3, &s.options)
+	s.next = 0
+}
+
+// Draw deals the next card in the shoe. ErrEmpty is returned once every card has been dealt.
+func (s *Shoe) Draw() (Card, error) {
+	if s.next >= len(s.cards) {
+		return Card{}, ErrEmpty
+	}
+	c := s.cards[s.next]
+	s.next++
+	return c, nil
+}
+
+// NeedsReshuffle reports whether the cut card has been reached.
+func (s *Shoe) NeedsReshuffle() bool {
+	return s.next >= s.cut
+}
+
+// Remaining returns the number of cards that have not been dealt yet.
+func (s *Shoe) Remaining() int {
+	return len(s.cards) - s.next
+}
+
+// Dealt returns the number of cards dealt since the last shuffle.
+func (s *Shoe) Dealt() int {
+	return s.next
+}
+
+// Size returns the total number of cards in the shoe.
+func (s *Shoe) Size() int {
+	return len(s.cards)
+}
+
+// Decks returns the number of decks the shoe was built with.
+func (s *Shoe) Decks() int {
+	return s.decks
+}
+
+// DecksRemaining returns the number of decks left to be dealt, which is
+// used to convert a running count into a true count.
+func (s *Shoe) DecksRemaining() float64 {
+	return float64(s.Remaining()) / float64(len(s.cards)) * float64(s.decks)
+}
+
+// Penetration returns the fraction of the shoe dealt before the cut card.
+func (s *Shoe) Penetration() float64 {
+	return s.penetration
+}
+
+// CutCard returns the number of cards that are dealt before the cut card is reached.
+func (s *Shoe) CutCard() int {
+	return s.cut
+}
+
+func (s *Shoe) placeCutCard() {
+	s.cut = int(math.Round(float64(len(s.cards)) * s.penetration))
+}
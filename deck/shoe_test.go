@@ -0,0 +1,93 @@
+package deck
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewShoe(t *testing.T) {
+	s, err := NewShoe(6, 0.75, WithShuffle(), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Size() != 312 {
+		t.Fatalf("expected 312 cards, got %d", s.Size())
+	}
+	if s.CutCard() != 234 {
+		t.Fatalf("expected cut card at 234, got %d", s.CutCard())
+	}
+
+	counts := make(map[Card]int)
+	for s.Remaining() > 0 {
+		c, err := s.Draw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[c]++
+	}
+	for c, n := range counts {
+		if n != 6 {
+			t.Fatalf("expected 6 of %s, got %d", c, n)
+		}
+	}
+}
+
+func TestShoeInvalidArguments(t *testing.T) {
+	if _, err := NewShoe(0, 0.5); err == nil {
+		t.Fatal("expected error for zero decks")
+	}
+	if _, err := NewShoe(1, 0); err == nil {
+		t.Fatal("expected error for zero penetration")
+	}
+	if _, err := NewShoe(1, 1.5); err == nil {
+		t.Fatal("expected error for penetration above 1")
+	}
+}
+
+func TestShoeCutCard(t *testing.T) {
+	s, err := NewShoe(1, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 26; i++ {
+		if s.NeedsReshuffle() {
+			t.Fatalf("reshuffle due after only %d cards", i)
+		}
+		s.Draw()
+	}
+	if !s.NeedsReshuffle() {
+		t.Fatal("expected reshuffle to be due at the cut card")
+	}
+
+	for s.Remaining() > 0 {
+		s.Draw()
+	}
+	if _, err := s.Draw(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+
+	s.Shuffle()
+	if s.Remaining() != 52 || s.NeedsReshuffle() {
+		t.Fatalf("expected full shoe after shuffle, got %d cards", s.Remaining())
+	}
+}
+
+func TestNewShoeFromCards(t *testing.T) {
+	cards := NewDeck(WithShuffle(), WithSeed(2))
+	s, err := NewShoeFromCards(cards, 1, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range cards {
+		c, err := s.Draw()
+		if err != nil || c != want {
+			t.Fatalf("expected %s at position %d, got %s (%v)", want, i, c, err)
+		}
+	}
+	if _, err := NewShoeFromCards(nil, 1, 0.5); err == nil {
+		t.Fatal("expected error for empty shoe")
+	}
+}
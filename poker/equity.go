@@ -0,0 +1,356 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Number of Monte Carlo trials run with the same random source. Trials are split
+// into fixed chunks so the result for a seed does not depend on the number of workers.
+const equityChunk = 10000
+
+// Type EquityOptions is used to configure CalculateEquity.
+//
+// Trials: number of random runouts dealt in Monte Carlo mode. Defaults to 100,000.
+// Set with WithTrials function
+//
+// Exhaustive: option to enumerate every possible runout instead of sampling them.
+// Enable with WithExhaustive function
+//
+// Seed: seed used to deal the runouts, the same seed always gives the same result.
+// When not set a random seed is used. Set with WithSeed function
+//
+// Workers: number of goroutines used. Defaults to the number of CPUs. Set with WithWorkers function
+type EquityOptions struct {
+	Trials     int
+	Exhaustive bool
+	Seed       *int64
+	Workers    int
+}
+
+// type EquityOptionFunc acts a wrapper for functional
+// options used for configuration in CalculateEquity
+type EquityOptionFunc func(*EquityOptions)
+
+// Option that sets the number of runouts dealt in Monte Carlo mode.
+func WithTrials(n int) EquityOptionFunc {
+	return func(o *EquityOptions) {
+		o.Trials = n
+	}
+}
+
+// Option to enumerate every possible runout instead of sampling them.
+func WithExhaustive() EquityOptionFunc {
+	return func(o *EquityOptions) {
+		o.Exhaustive = true
+	}
+}
+
+// Option that makes the Monte Carlo simulation deterministic.
+func WithSeed(seed int64) EquityOptionFunc {
+	return func(o *EquityOptions) {
+		o.Seed = &seed
+	}
+}
+
+// Option that sets the number of goroutines used.
+func WithWorkers(n int) EquityOptionFunc {
+	return func(o *EquityOptions) {
+		o.Workers = n
+	}
+}
+
+// Equity holds how often a player wins, ties and loses over the runouts, in percent.
+// Equity is the share of the pots the player is expected to win, counting a
+// tie between n players as 1/n of a win.
+type Equity struct {
+	Win    float64
+	Tie    float64
+	Loss   float64
+	Equity float64
+}
+
+func (e Equity) String() string {
+	return fmt.Sprintf("win %.2f%% tie %.2f%% loss %.2f%% equity %.2f%%", e.Win, e.Tie, e.Loss, e.Equity)
+}
+
+// CalculateEquity deals the rest of the board for the Texas Hold'em hole cards in hands
+// and returns the equity of every player, in the same order as hands. The board can
+// hold 0 to 5 cards.
+func CalculateEquity(hands [][]deck.Card, board []deck.Card, options ...EquityOptionFunc) ([]Equity, error) {
+	o := EquityOptions{
+		Trials:  100000,
+		Workers: runtime.NumCPU(),
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	if o.Trials < 1 && !o.Exhaustive {
+		return nil, fmt.Errorf("poker: trials must be positive, got %d", o.Trials)
+	}
+	o.Workers = max(o.Workers, 1)
+
+	known, err := validateEquity(hands, board)
+	if err != nil {
+		return nil, err
+	}
+	remaining := deck.NewDeck(deck.WithFilter(func(c deck.Card) bool {
+		return known[c]
+	}))
+
+	e := equityCounter{
+		hands:   hands,
+		board:   board,
+		missing: 5 - len(board),
+	}
+
+	var t tally
+	switch {
+	case e.missing == 0:
+		t = e.newTally()
+		e.runout(nil, &t)
+	case o.Exhaustive:
+		t = e.enumerate(remaining, o.Workers)
+	default:
+		seed := rand.Int63()
+		if o.Seed != nil {
+			seed = *o.Seed
+		}
+		t = e.sample(remaining, o.Trials, seed, o.Workers)
+	}
+
+	return t.equities(), nil
+}
+
+func validateEquity(hands [][]deck.Card, board []deck.Card) (map[deck.Card]bool, error) {
+	if len(hands) < 2 || len(hands) > maxPlayers {
+		return nil, fmt.Errorf("poker: equity needs 2 to %d hands, got %d", maxPlayers, len(hands))
+	}
+	if len(board) > 5 {
+		return nil, fmt.Errorf("poker: board can have at most 5 cards, got %d", len(board))
+	}
+
+	known := make(map[deck.Card]bool)
+	add := func(c deck.Card) error {
+		if c.Suit == deck.JOKER {
+			return errors.New("poker: jokers can not be played")
+		}
+		// Score panics on cards outside a standard deck.
+		if c.Suit < deck.SPADE || c.Suit > deck.HEART || c.Type < deck.ACE || c.Type > deck.KING {
+			return fmt.Errorf("poker: %v is not a playing card", c)
+		}
+		if known[c] {
+			return fmt.Errorf("poker: %s is dealt more than once", c)
+		}
+		known[c] = true
+		return nil
+	}
+
+	for i, h := range hands {
+		if len(h) != 2 {
+			return nil, fmt.Errorf("poker: hand %d must have 2 cards, got %d", i, len(h))
+		}
+		for _, c := range h {
+			if err := add(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, c := range board {
+		if err := add(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return known, nil
+}
+
+type equityCounter struct {
+	hands   [][]deck.Card
+	board   []deck.Card
+	missing int
+}
+
+// tally counts the outcome of every runout for each player. share is the
+// number of pots won multiplied by the number of ways a pot can be split
+// so it can be counted exactly with integers.
+type tally struct {
+	runouts int
+	wins    []int
+	ties    []int
+	share   []int
+}
+
+// Texas Hold'em is played by at most 10 players and every
+// split of 2 to 10 ways divides splitUnit.
+const (
+	maxPlayers = 10
+	splitUnit  = 2520
+)
+
+func (e *equityCounter) newTally() tally {
+	n := len(e.hands)
+	return tally{
+		wins:  make([]int, n),
+		ties:  make([]int, n),
+		share: make([]int, n),
+	}
+}
+
+func (t *tally) add(o tally) {
+	t.runouts += o.runouts
+	for i := range t.wins {
+		t.wins[i] += o.wins[i]
+		t.ties[i] += o.ties[i]
+		t.share[i] += o.share[i]
+	}
+}
+
+func (t tally) equities() []Equity {
+	equities := make([]Equity, len(t.wins))
+	n := float64(t.runouts)
+	for i := range equities {
+		equities[i] = Equity{
+			Win:    100 * float64(t.wins[i]) / n,
+			Tie:    100 * float64(t.ties[i]) / n,
+			Loss:   100 * float64(t.runouts-t.wins[i]-t.ties[i]) / n,
+			Equity: 100 * float64(t.share[i]) / splitUnit / n,
+		}
+	}
+	return equities
+}
+
+// runout scores every hand against the board completed with extra and records the winners.
+func (e *equityCounter) runout(extra []deck.Card, t *tally) {
+	var (
+		cards  [7]deck.Card
+		scores [maxPlayers]Value
+		best   Value
+	)
+	n := copy(cards[2:], e.board)
+	copy(cards[2+n:], extra)
+
+	winners := 0
+	for i, h := range e.hands {
+		cards[0], cards[1] = h[0], h[1]
+		scores[i] = Score(cards[:]...)
+		switch {
+		case scores[i] > best:
+			best, winners = scores[i], 1
+		case scores[i] == best:
+			winners++
+		}
+	}
+
+	t.runouts++
+	for i := range e.hands {
+		if scores[i] != best {
+			continue
+		}
+		if winners == 1 {
+			t.wins[i]++
+		} else {
+			t.ties[i]++
+		}
+		t.share[i] += splitUnit / winners
+	}
+}
+
+// sample deals random runouts in parallel. Every chunk of trials uses its own source
+// derived from seed so the result does not depend on scheduling.
+func (e *equityCounter) sample(remaining []deck.Card, trials int, seed int64, workers int) tally {
+	chunks := (trials + equityChunk - 1) / equityChunk
+	jobs := make(chan int)
+	results := make(chan tally)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cards := make([]deck.Card, len(remaining))
+			for chunk := range jobs {
+				t := e.newTally()
+				r := rand.New(rand.NewSource(seed + int64(chunk)*0x9e3779b9))
+				copy(cards, remaining)
+				n := min(equityChunk, trials-chunk*equityChunk)
+				for i := 0; i < n; i++ {
+					// Partial Fisher-Yates, only the missing board cards need to be random.
+					for j := 0; j < e.missing; j++ {
+						k := j + r.Intn(len(cards)-j)
+						cards[j], cards[k] = cards[k], cards[j]
+					}
+					e.runout(cards[:e.missing], &t)
+				}
+				results <- t
+			}
+		}()
+	}
+
+	go func() {
+		for chunk := 0; chunk < chunks; chunk++ {
+			jobs <- chunk
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	total := e.newTally()
+	for t := range results {
+		total.add(t)
+	}
+	return total
+}
+
+// enumerate deals every possible runout in parallel, splitting the work by the first card dealt.
+func (e *equityCounter) enumerate(remaining []deck.Card, workers int) tally {
+	jobs := make(chan int)
+	results := make(chan tally)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			extra := make([]deck.Card, e.missing)
+			for first := range jobs {
+				t := e.newTally()
+				extra[0] = remaining[first]
+				e.combinations(remaining, first+1, extra, 1, &t)
+				results <- t
+			}
+		}()
+	}
+
+	go func() {
+		for first := 0; first+e.missing <= len(remaining); first++ {
+			jobs <- first
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	total := e.newTally()
+	for t := range results {
+		total.add(t)
+	}
+	return total
+}
+
+func (e *equityCounter) combinations(remaining []deck.Card, from int, extra []deck.Card, n int, t *tally) {
+	if n == len(extra) {
+		e.runout(extra, t)
+		return
+	}
+	for i := from; i+len(extra)-n <= len(remaining); i++ {
+		extra[n] = remaining[i]
+		e.combinations(remaining, i+1, extra, n+1, t)
+	}
+}
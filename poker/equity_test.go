@@ -0,0 +1,99 @@
+package poker
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func TestEquityRiver(t *testing.T) {
+	hands := [][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "Kc Kd"), mustParse(t, "Qc Qd")}
+	board := mustParse(t, "2h 7h 9c Js Kh")
+
+	equities, err := CalculateEquity(hands, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Equity{{0, 0, 100, 0}, {100, 0, 0, 100}, {0, 0, 100, 0}}
+	if !slices.Equal(equities, want) {
+		t.Fatalf("expected %v, got %v", want, equities)
+	}
+}
+
+func TestEquitySplitPot(t *testing.T) {
+	hands := [][]deck.Card{mustParse(t, "2c 3d"), mustParse(t, "2h 3s")}
+	board := mustParse(t, "Ah Kd Qs Jc Th")
+
+	equities, err := CalculateEquity(hands, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range equities {
+		if e.Tie != 100 || e.Equity != 50 {
+			t.Fatalf("expected chopped pot, got %v", e)
+		}
+	}
+}
+
+func TestEquityExhaustive(t *testing.T) {
+	hands := [][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "Kc Kd")}
+	board := mustParse(t, "Kh 7s 2d")
+
+	equities, err := CalculateEquity(hands, board, WithExhaustive())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Kings have flopped a set, aces only win if one of the two aces left comes
+	// without the last king. That is 85 of the 990 turn and river combinations.
+	if want := 100 * 85.0 / 990; math.Abs(equities[0].Win-want) > 1e-9 {
+		t.Fatalf("expected aces to win %.4f%%, got %v", want, equities[0])
+	}
+	if math.Abs(equities[0].Win+equities[1].Win+equities[0].Tie-100) > 1e-9 {
+		t.Fatalf("expected outcomes to add up to 100%%, got %v", equities)
+	}
+}
+
+func TestEquityMonteCarlo(t *testing.T) {
+	hands := [][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "Kc Kd")}
+
+	a, err := CalculateEquity(hands, nil, WithSeed(7), WithTrials(50000), WithWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := CalculateEquity(hands, nil, WithSeed(7), WithTrials(50000), WithWorkers(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(a, b) {
+		t.Fatalf("expected same result for the same seed, got %v and %v", a, b)
+	}
+
+	// Aces are about an 82% favourite over kings before the flop.
+	if math.Abs(a[0].Equity-82) > 1 {
+		t.Fatalf("expected about 82%% equity for aces, got %v", a[0])
+	}
+}
+
+func TestEquityInvalidInput(t *testing.T) {
+	tests := map[string]struct {
+		hands [][]deck.Card
+		board []deck.Card
+	}{
+		"one player":     {[][]deck.Card{mustParse(t, "As Ah")}, nil},
+		"duplicate card": {[][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "As Kd")}, nil},
+		"board card":     {[][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "Kc Kd")}, mustParse(t, "Ah 2c 3c")},
+		"three cards":    {[][]deck.Card{mustParse(t, "As Ah 2c"), mustParse(t, "Kc Kd")}, nil},
+		"bad suit":       {[][]deck.Card{mustParse(t, "As Ah"), {{Suit: 9, Type: deck.KING}, {Suit: deck.CLUB, Type: deck.KING}}}, nil},
+		"bad rank":       {[][]deck.Card{mustParse(t, "As Ah"), mustParse(t, "Kc Kd")}, []deck.Card{{Suit: deck.CLUB, Type: deck.NONE}}},
+	}
+
+	for name, test := range tests {
+		if _, err := CalculateEquity(test.hands, test.board); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
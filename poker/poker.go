@@ -0,0 +1,253 @@
+// Package poker ranks poker hands made of deck.Card values. Hands of five to
+// seven cards are scored by their best five card combination, which makes
+// the package suitable for Texas Hold'em and Seven Card Stud.
+package poker
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+// Category represents the category of a poker hand (e.g Flush).
+type Category uint8
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var categoryNames = [...]string{
+	"High Card",
+	"One Pair",
+	"Two Pair",
+	"Three of a Kind",
+	"Straight",
+	"Flush",
+	"Full House",
+	"Four of a Kind",
+	"Straight Flush",
+}
+
+func (c Category) String() string {
+	if int(c) >= len(categoryNames) {
+		return fmt.Sprintf("Category(%d)", c)
+	}
+	return categoryNames[c]
+}
+
+// Rank is the value of a card in poker, from 2 (Two) to 14 (Ace).
+type Rank uint8
+
+// RankOf returns the poker rank of a card, where an ace ranks highest.
+func RankOf(c deck.Card) Rank {
+	if c.Type == deck.ACE {
+		return 14
+	}
+	return Rank(c.Type)
+}
+
+// Value totally orders poker hands, a higher value beats a lower one and
+// equal values split the pot. The category is stored in the bits above 20
+// and the five ranks that break ties within it in four bits each below.
+type Value uint32
+
+// Category returns the category of the hand the value was computed for.
+func (v Value) Category() Category {
+	return Category(v >> 20)
+}
+
+// Hand is the result of evaluating a poker hand.
+//
+// Ranks holds the ranks that decide between two hands of the same category in
+// order of importance: first the ranks that make the category (e.g the rank of the
+// trips and then the pair in a full house) followed by the kickers.
+type Hand struct {
+	Category Category
+	Ranks    []Rank
+	Value    Value
+}
+
+// Kickers returns the ranks of the cards that are not part of the category.
+func (h Hand) Kickers() []Rank {
+	made := 1
+	switch h.Category {
+	case TwoPair, FullHouse:
+		made = 2
+	case Flush:
+		made = 5
+	}
+	return h.Ranks[min(made, len(h.Ranks)):]
+}
+
+func (h Hand) String() string {
+	ranks := make([]string, len(h.Ranks))
+	for i, r := range h.Ranks {
+		ranks[i] = fmt.Sprint(r)
+	}
+	return fmt.Sprintf("%s (%s)", h.Category, strings.Join(ranks, " "))
+}
+
+// Compare returns a positive number if a beats b, a negative number if b beats a and 0 for a tie.
+func Compare(a, b Hand) int {
+	switch {
+	case a.Value > b.Value:
+		return 1
+	case a.Value < b.Value:
+		return -1
+	}
+	return 0
+}
+
+// Evaluate scores the best five card hand out of 5 to 7 cards. It panics if
+// it is given a different number of cards or a Joker.
+func Evaluate(cards ...deck.Card) Hand {
+	v := Score(cards...)
+	return Hand{
+		Category: v.Category(),
+		Ranks:    v.ranks(),
+		Value:    v,
+	}
+}
+
+// Score is like Evaluate but only computes the value of the hand, it does
+// not allocate and is meant for simulations scoring millions of hands.
+func Score(cards ...deck.Card) Value {
+	if len(cards) < 5 || len(cards) > 7 {
+		panic(fmt.Sprintf("poker: can only score 5 to 7 cards, got %d", len(cards)))
+	}
+
+	var (
+		suits  [4]uint16 // bit r set if rank r is held in that suit
+		counts [15]uint8
+		ranks  uint16
+	)
+	for _, c := range cards {
+		if c.Suit < deck.SPADE || c.Suit > deck.HEART || c.Type < deck.ACE || c.Type > deck.KING {
+			panic(fmt.Sprintf("poker: can not score %v", c))
+		}
+		r := RankOf(c)
+		suits[c.Suit] |= 1 << r
+		counts[r]++
+		ranks |= 1 << r
+	}
+
+	for _, s := range suits {
+		if bits.OnesCount16(s) >= 5 {
+			if high := straightHigh(s); high > 0 {
+				return put(StraightFlush.value(), 0, high)
+			}
+			return putTop(Flush.value(), 0, s, 5)
+		}
+	}
+
+	// Group ranks by how many times they are held.
+	var quads, trips, pairs, singles uint16
+	for r := 2; r <= 14; r++ {
+		switch counts[r] {
+		case 4:
+			quads |= 1 << r
+		case 3:
+			trips |= 1 << r
+		case 2:
+			pairs |= 1 << r
+		case 1:
+			singles |= 1 << r
+		}
+	}
+
+	switch {
+	case quads != 0:
+		q := highest(quads)
+		return putTop(put(FourOfAKind.value(), 0, q), 1, ranks&^(1<<q), 1)
+	case trips != 0 && (bits.OnesCount16(trips) > 1 || pairs != 0):
+		t := highest(trips)
+		// A second set of trips can fill the pair as well.
+		return putTop(put(FullHouse.value(), 0, t), 1, (trips&^(1<<t))|pairs, 1)
+	}
+
+	if high := straightHigh(ranks); high > 0 {
+		return put(Straight.value(), 0, high)
+	}
+
+	switch {
+	case trips != 0:
+		return putTop(put(ThreeOfAKind.value(), 0, highest(trips)), 1, singles, 2)
+	case bits.OnesCount16(pairs) >= 2:
+		high := highest(pairs)
+		low := highest(pairs &^ (1 << high))
+		return putTop(put(put(TwoPair.value(), 0, high), 1, low), 2, ranks&^(1<<high|1<<low), 1)
+	case pairs != 0:
+		return putTop(put(OnePair.value(), 0, highest(pairs)), 1, singles, 3)
+	}
+	return putTop(HighCard.value(), 0, ranks, 5)
+}
+
+// straightHigh returns the highest card of the best straight in the rank set or 0 if there is none.
+func straightHigh(ranks uint16) Rank {
+	if ranks&(1<<14) != 0 {
+		ranks |= 1 << 1 // the ace also plays low in A-2-3-4-5
+	}
+	for high := 14; high >= 5; high-- {
+		mask := uint16(0x1f) << (high - 4)
+		if ranks&mask == mask {
+			return Rank(high)
+		}
+	}
+	return 0
+}
+
+func highest(ranks uint16) Rank {
+	return Rank(15 - bits.LeadingZeros16(ranks))
+}
+
+func (c Category) value() Value {
+	return Value(c) << 20
+}
+
+// put stores rank r as the i-th most important rank of the value.
+func put(v Value, i int, r Rank) Value {
+	return v | Value(r)<<(16-4*i)
+}
+
+// putTop stores the n highest ranks of the set starting at the i-th most important rank.
+func putTop(v Value, i int, ranks uint16, n int) Value {
+	for ; n > 0 && ranks != 0; n-- {
+		r := highest(ranks)
+		v = put(v, i, r)
+		ranks &^= 1 << r
+		i++
+	}
+	return v
+}
+
+func (v Value) ranks() []Rank {
+	var n int
+	switch v.Category() {
+	case StraightFlush, Straight:
+		n = 1
+	case FourOfAKind, FullHouse:
+		n = 2
+	case TwoPair, ThreeOfAKind:
+		n = 3
+	case OnePair:
+		n = 4
+	default:
+		n = 5
+	}
+
+	ranks := make([]Rank, n)
+	for i := range ranks {
+		ranks[i] = Rank(v >> (16 - 4*i) & 0xf)
+	}
+	return ranks
+}
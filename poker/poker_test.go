@@ -0,0 +1,132 @@
+package poker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Junior-Green/gophercises/deck"
+)
+
+func mustParse(t testing.TB, s string) []deck.Card {
+	t.Helper()
+	cards, err := deck.ParseDeck(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		cards    string
+		category Category
+		ranks    []Rank
+	}{
+		{"As Ks Qs Js Ts", StraightFlush, []Rank{14}},
+		{"5d 4d 3d 2d Ad", StraightFlush, []Rank{5}},
+		{"9c 9d 9h 9s 2c", FourOfAKind, []Rank{9, 2}},
+		{"3c 3d 3h Ks Kc", FullHouse, []Rank{3, 13}},
+		{"2h 7h 9h Jh Kh", Flush, []Rank{13, 11, 9, 7, 2}},
+		{"5c 4d 3h 2s Ac", Straight, []Rank{5}},
+		{"Qc Qd Qh 7s 2c", ThreeOfAKind, []Rank{12, 7, 2}},
+		{"Jc Jd 4h 4s Ac", TwoPair, []Rank{11, 4, 14}},
+		{"Tc Td 8h 4s 2c", OnePair, []Rank{10, 8, 4, 2}},
+		{"Kc Td 8h 4s 2c", HighCard, []Rank{13, 10, 8, 4, 2}},
+		// Seven card hands use the best five cards.
+		{"As Ad Ah Ks Kd Kh 2c", FullHouse, []Rank{14, 13}},
+		{"Jc Jd 4h 4s 3c 3d Ac", TwoPair, []Rank{11, 4, 14}},
+		{"2h 3h 4h 5h 6h 7h 8c", StraightFlush, []Rank{7}},
+		{"2h 3h 4c 5h 6d 9h Ah", Flush, []Rank{14, 9, 5, 3, 2}},
+		{"Qc Qd 7h 7s 9c 9d 2c", TwoPair, []Rank{12, 9, 7}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cards, func(t *testing.T) {
+			h := Evaluate(mustParse(t, test.cards)...)
+			if h.Category != test.category {
+				t.Fatalf("expected %s, got %s", test.category, h.Category)
+			}
+			if !slices.Equal(h.Ranks, test.ranks) {
+				t.Fatalf("expected ranks %v, got %v", test.ranks, h.Ranks)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"As Ks Qs Js Ts", "9c 9d 9h 9s Ac", 1},
+		{"5c 4d 3h 2s Ac", "6c 5d 4h 3s 2c", -1},
+		{"Jc Jd 4h 4s Ac", "Jh Js 4c 4d Kc", 1},
+		{"Tc Td 8h 4s 2c", "Th Ts 8c 4d 2d", 0},
+		{"Kc Kd 2h 2s 3c", "Qc Qd Jh Js Ac", 1},
+	}
+
+	for _, test := range tests {
+		got := Compare(Evaluate(mustParse(t, test.a)...), Evaluate(mustParse(t, test.b)...))
+		if got != test.want {
+			t.Fatalf("%s vs %s: expected %d, got %d", test.a, test.b, test.want, got)
+		}
+	}
+}
+
+func TestKickers(t *testing.T) {
+	h := Evaluate(mustParse(t, "Jc Jd 4h 4s Ac")...)
+	if !slices.Equal(h.Kickers(), []Rank{14}) {
+		t.Fatalf("expected kicker A, got %v", h.Kickers())
+	}
+}
+
+// TestAllFiveCardHands checks the number of hands in every category over
+// all 2,598,960 five card hands.
+func TestAllFiveCardHands(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping exhaustive enumeration in short mode")
+	}
+
+	want := map[Category]int{
+		StraightFlush: 40,
+		FourOfAKind:   624,
+		FullHouse:     3744,
+		Flush:         5108,
+		Straight:      10200,
+		ThreeOfAKind:  54912,
+		TwoPair:       123552,
+		OnePair:       1098240,
+		HighCard:      1302540,
+	}
+
+	cards := deck.NewDeck()
+	got := make(map[Category]int)
+	hand := make([]deck.Card, 5)
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			for c := b + 1; c < 52; c++ {
+				for d := c + 1; d < 52; d++ {
+					for e := d + 1; e < 52; e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = cards[a], cards[b], cards[c], cards[d], cards[e]
+						got[Score(hand...).Category()]++
+					}
+				}
+			}
+		}
+	}
+
+	for c, n := range want {
+		if got[c] != n {
+			t.Fatalf("expected %d hands of %s, got %d", n, c, got[c])
+		}
+	}
+}
+
+func BenchmarkScore7(b *testing.B) {
+	cards := deck.NewDeck(deck.WithShuffle(), deck.WithSeed(1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		j := i % 45
+		Score(cards[j : j+7]...)
+	}
+}